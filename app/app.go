@@ -3,8 +3,11 @@ package app
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"runtime"
 
+	"github.com/joseluisq/cline/config"
 	"github.com/joseluisq/cline/flag"
 )
 
@@ -26,6 +29,61 @@ type App struct {
 	Commands []Cmd
 	// The application action handler.
 	Handler AppHandler
+	// An optional command name (or alias) to run when no command word is provided.
+	// Its flags and positional arguments are accepted without the command word.
+	DefaultCommand string
+	// Report an unknown command error when the first positional argument is not a declared command.
+	// It only applies when commands are declared.
+	StrictCommands bool
+	// An optional handler for an unrecognized first positional argument when commands are declared.
+	// It receives the unrecognized word and the remaining arguments instead of reporting an error.
+	NotFound NotFoundHandler
+	// An optional environment variable (e.g. `TOOL_OPTS`) containing default flag arguments which are
+	// split using shell quoting rules and applied before the command line ones, so the latter override them.
+	// When it ends with `_OPTS` then commands also get their own variable (e.g. `TOOL_INFO_OPTS`)
+	// applied whenever they are selected, including the default command.
+	OptsEnvVar string
+	// An optional environment variable prefix (e.g. `TOOL`) used to derive the variable names
+	// of the flags without one (e.g. `TOOL_TRACE` or `TOOL_INFO_TRACE` for the `info` command flags).
+	EnvPrefix string
+	// An optional application string or path flag name (e.g. `config`) containing the path of a config file.
+	// Config values apply to the flags not provided via the command line or environment variables.
+	ConfigFlag string
+	// Optional config file paths to search in order when no config file path is provided.
+	// When `ConfigFlag` is set they default to the `config` files of the application config directory
+	// (e.g. `~/.config/<name>/config.json`).
+	ConfigPaths []string
+	// Optional config file decoders by file extension (e.g. `.toml` or `.yaml`) besides the built-in `.json` one.
+	ConfigDecoders map[string]config.Decoder
+	// An optional function to look up all environment variables (e.g. `flag.LookupEnvMap`).
+	// It defaults to the process environment.
+	LookupEnv flag.LookupEnvFunc
+	// Optional dotenv files (e.g. `.env`) loaded in order before resolving the flag environment variables.
+	// Files which do not exist are skipped and the process environment is not modified unless `EnvSetenv` is set.
+	EnvFiles []string
+	// Let the dotenv files variables override the process environment and the previous files ones.
+	EnvOverride bool
+	// Export the dotenv files variables to the process environment before running the handlers.
+	EnvSetenv bool
+	// Expand the `$VAR`, `${VAR}` or `${VAR:-default}` references of the flag default values and of the values
+	// provided via the command line, environment variables or config files using the environment lookup.
+	// Path flags always expand their values.
+	ExpandEnv bool
+	// Enable the built-in `--print-config [table|json]` flag which prints the effective flag values and their sources.
+	PrintConfig bool
+	// Select the command to run via the program name (`argv[0]`) like busybox-style multi-call binaries.
+	// Help and error messages use the invoked program name instead of the application name.
+	MultiCall bool
+	// Dispatch unrecognized commands to external executables named `<app name>-<command>` (plugins).
+	Plugins bool
+	// Optional directories to look up plugin executables before PATH.
+	PluginDirs []string
+	// An optional output writer used by all printing. It defaults to `os.Stdout`.
+	Writer io.Writer
+	// An optional error writer used by all error printing. It defaults to `os.Stderr`.
+	ErrWriter io.Writer
+	// An optional input reader. It defaults to `os.Stdin`.
+	Reader io.Reader
 }
 
 // New creates a new application instance.
@@ -33,11 +91,36 @@ func New() *App {
 	return &App{}
 }
 
+// Stdout gets the application output writer or `os.Stdout` if not set.
+func (ap *App) Stdout() io.Writer {
+	if ap == nil || ap.Writer == nil {
+		return os.Stdout
+	}
+	return ap.Writer
+}
+
+// Stderr gets the application error writer or `os.Stderr` if not set.
+func (ap *App) Stderr() io.Writer {
+	if ap == nil || ap.ErrWriter == nil {
+		return os.Stderr
+	}
+	return ap.ErrWriter
+}
+
+// Stdin gets the application input reader or `os.Stdin` if not set.
+func (ap *App) Stdin() io.Reader {
+	if ap == nil || ap.Reader == nil {
+		return os.Stdin
+	}
+	return ap.Reader
+}
+
 // PrintVersion prints the current application version information (--version, -v).
 func (ap *App) PrintVersion() {
-	fmt.Printf("Version:       %s\n", ap.Version)
-	fmt.Printf("Go version:    %s\n", runtime.Version())
-	fmt.Printf("Built:         %s\n", ap.BuildTime)
-	fmt.Printf("Commit:        %s\n", ap.BuildCommit)
-	fmt.Printf("OS/Arch:       %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	w := ap.Stdout()
+	fmt.Fprintf(w, "Version:       %s\n", ap.Version)
+	fmt.Fprintf(w, "Go version:    %s\n", runtime.Version())
+	fmt.Fprintf(w, "Built:         %s\n", ap.BuildTime)
+	fmt.Fprintf(w, "Commit:        %s\n", ap.BuildCommit)
+	fmt.Fprintf(w, "OS/Arch:       %s/%s\n", runtime.GOOS, runtime.GOARCH)
 }
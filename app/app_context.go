@@ -1,6 +1,9 @@
 package app
 
 import (
+	"context"
+	"io"
+
 	"github.com/joseluisq/cline/flag"
 )
 
@@ -9,6 +12,8 @@ type AppContext struct {
 	app      *App
 	flags    *flag.FlagValues
 	tailArgs []string
+	ctx      context.Context
+	lookup   flag.LookupEnvFunc
 }
 
 // NewContext creates a new application context.
@@ -20,6 +25,22 @@ func NewContext(app *App, flagValues *flag.FlagValues, tailArgs []string) *AppCo
 	}
 }
 
+// WithContext returns a shallow copy of the application context
+// carrying the given `context.Context`.
+func (c *AppContext) WithContext(ctx context.Context) *AppContext {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+// WithLookupEnv returns a shallow copy of the application context
+// looking up the environment variables via the given function (e.g. including the dotenv files variables).
+func (c *AppContext) WithLookupEnv(lookup flag.LookupEnvFunc) *AppContext {
+	c2 := *c
+	c2.lookup = lookup
+	return &c2
+}
+
 // App gets a reference of the current application instance.
 func (c *AppContext) App() *App {
 	return c.app
@@ -34,3 +55,56 @@ func (c *AppContext) Flags() *flag.FlagValues {
 func (c *AppContext) TailArgs() []string {
 	return c.tailArgs
 }
+
+// Context gets the `context.Context` of the current execution.
+// It is cancelled when the handler receives an interrupt signal (if enabled)
+// and it defaults to `context.Background()` if none was provided.
+func (c *AppContext) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// Stdout gets the application output writer. See `App.Writer`.
+func (c *AppContext) Stdout() io.Writer {
+	return c.app.Stdout()
+}
+
+// Stderr gets the application error writer. See `App.ErrWriter`.
+func (c *AppContext) Stderr() io.Writer {
+	return c.app.Stderr()
+}
+
+// Stdin gets the application input reader. See `App.Reader`.
+func (c *AppContext) Stdin() io.Reader {
+	return c.app.Stdin()
+}
+
+// ConfigDir gets the application config directory via the context lookup function. See `App.UserDir`.
+func (c *AppContext) ConfigDir() string {
+	return c.app.UserDir(DirConfig, c.lookupEnv())
+}
+
+// CacheDir gets the application cache directory via the context lookup function. See `App.UserDir`.
+func (c *AppContext) CacheDir() string {
+	return c.app.UserDir(DirCache, c.lookupEnv())
+}
+
+// DataDir gets the application data directory via the context lookup function. See `App.UserDir`.
+func (c *AppContext) DataDir() string {
+	return c.app.UserDir(DirData, c.lookupEnv())
+}
+
+// StateDir gets the application state directory via the context lookup function. See `App.UserDir`.
+func (c *AppContext) StateDir() string {
+	return c.app.UserDir(DirState, c.lookupEnv())
+}
+
+// lookupEnv returns the context environment lookup function falling back to the application one.
+func (c *AppContext) lookupEnv() flag.LookupEnvFunc {
+	if c.lookup != nil {
+		return c.lookup
+	}
+	return c.app.lookupEnv()
+}
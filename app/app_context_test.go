@@ -1,6 +1,10 @@
 package app
 
 import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -113,3 +117,55 @@ func TestAppContext_TailArgs(t *testing.T) {
 		assert.Nil(t, got, "TailArgs() should return nil for nil tail arguments")
 	})
 }
+
+func TestAppContext_Context(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("should return a background context by default", func(t *testing.T) {
+		c := NewContext(nil, nil, nil)
+		assert.Equal(t, context.Background(), c.Context())
+	})
+
+	t.Run("should return the provided context", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+		c := NewContext(nil, nil, nil)
+		c2 := c.WithContext(ctx)
+		assert.Equal(t, "value", c2.Context().Value(ctxKey{}))
+		assert.Equal(t, context.Background(), c.Context(), "original context should not be modified")
+	})
+
+	t.Run("should return the app context from a command context", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+		cmdCtx := &CmdContext{AppContext: NewContext(nil, nil, nil).WithContext(ctx)}
+		assert.Equal(t, "value", cmdCtx.Context().Value(ctxKey{}))
+		assert.Equal(t, context.Background(), (&CmdContext{}).Context())
+	})
+}
+
+func TestAppContext_Streams(t *testing.T) {
+	t.Run("should default to the os streams", func(t *testing.T) {
+		c := NewContext(&App{}, nil, nil)
+		assert.Equal(t, os.Stdout, c.Stdout())
+		assert.Equal(t, os.Stderr, c.Stderr())
+		assert.Equal(t, os.Stdin, c.Stdin())
+
+		cmdCtx := &CmdContext{}
+		assert.Equal(t, os.Stdout, cmdCtx.Stdout())
+		assert.Equal(t, os.Stderr, cmdCtx.Stderr())
+		assert.Equal(t, os.Stdin, cmdCtx.Stdin())
+	})
+
+	t.Run("should return the app streams", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		in := strings.NewReader("input")
+		c := NewContext(&App{Writer: &out, ErrWriter: &errOut, Reader: in}, nil, nil)
+		assert.Equal(t, &out, c.Stdout())
+		assert.Equal(t, &errOut, c.Stderr())
+		assert.Equal(t, in, c.Stdin())
+
+		cmdCtx := &CmdContext{AppContext: c}
+		assert.Equal(t, &out, cmdCtx.Stdout())
+		assert.Equal(t, &errOut, cmdCtx.Stderr())
+		assert.Equal(t, in, cmdCtx.Stdin())
+	})
+}
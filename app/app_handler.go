@@ -2,3 +2,7 @@ package app
 
 // AppHandler responds to an application action.
 type AppHandler func(*AppContext) error
+
+// NotFoundHandler responds to an unrecognized command
+// receiving its name and its remaining input arguments.
+type NotFoundHandler func(ctx *AppContext, name string, args []string) error
@@ -1,6 +1,9 @@
 package app
 
 import (
+	"context"
+	"io"
+
 	"github.com/joseluisq/cline/flag"
 )
 
@@ -8,10 +11,15 @@ import (
 type Cmd struct {
 	// The command name in alphanumeric format without special characters or spaces.
 	Name string
+	// An optional list of command aliases in alphanumeric format without special characters or spaces.
+	Aliases []string
 	// A brief command description.
 	Summary string
 	// The command flags.
 	Flags []flag.Flag
+	// An optional environment variable prefix for the command flags without one.
+	// It defaults to the application `EnvPrefix` followed by the command name (e.g. `TOOL_INFO`).
+	EnvPrefix string
 	// The command action handler.
 	Handler CmdHandler
 }
@@ -28,5 +36,37 @@ type CmdContext struct {
 	AppContext *AppContext
 }
 
+// Context gets the `context.Context` of the current execution
+// which is the one of its application context.
+func (c *CmdContext) Context() context.Context {
+	if c.AppContext == nil {
+		return context.Background()
+	}
+	return c.AppContext.Context()
+}
+
+// Stdout gets the application output writer. See `App.Writer`.
+func (c *CmdContext) Stdout() io.Writer {
+	return c.app().Stdout()
+}
+
+// Stderr gets the application error writer. See `App.ErrWriter`.
+func (c *CmdContext) Stderr() io.Writer {
+	return c.app().Stderr()
+}
+
+// Stdin gets the application input reader. See `App.Reader`.
+func (c *CmdContext) Stdin() io.Reader {
+	return c.app().Stdin()
+}
+
+// app gets the current application instance if any.
+func (c *CmdContext) app() *App {
+	if c.AppContext == nil {
+		return nil
+	}
+	return c.AppContext.app
+}
+
 // CmdHandler responds to a command action.
 type CmdHandler func(*CmdContext) error
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/joseluisq/cline/flag"
+)
+
+// DirKind defines the kind of an application user directory.
+type DirKind int
+
+const (
+	// DirConfig is the config directory (`$XDG_CONFIG_HOME` or `~/.config`).
+	DirConfig DirKind = iota
+	// DirCache is the cache directory (`$XDG_CACHE_HOME` or `~/.cache`).
+	DirCache
+	// DirData is the data directory (`$XDG_DATA_HOME` or `~/.local/share`).
+	DirData
+	// DirState is the state directory (`$XDG_STATE_HOME` or `~/.local/state`).
+	DirState
+)
+
+// UserDir returns the application user directory (e.g. `~/.config/<name>`) of a given kind following
+// the XDG base directory specification. The environment variables are looked up via a given function
+// (the process environment if nil) and non-absolute values are ignored as the specification states.
+// On Windows it falls back to `%APPDATA%` (config and data) or `%LOCALAPPDATA%` (cache and state).
+// It returns an empty string if the application name or the base directory is unknown.
+func (ap *App) UserDir(kind DirKind, lookup flag.LookupEnvFunc) string {
+	if ap == nil || ap.Name == "" {
+		return ""
+	}
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	var envVar, winEnvVar string
+	var fallback []string
+	switch kind {
+	case DirConfig:
+		envVar, winEnvVar, fallback = "XDG_CONFIG_HOME", "APPDATA", []string{".config"}
+	case DirCache:
+		envVar, winEnvVar, fallback = "XDG_CACHE_HOME", "LOCALAPPDATA", []string{".cache"}
+	case DirData:
+		envVar, winEnvVar, fallback = "XDG_DATA_HOME", "APPDATA", []string{".local", "share"}
+	case DirState:
+		envVar, winEnvVar, fallback = "XDG_STATE_HOME", "LOCALAPPDATA", []string{".local", "state"}
+	default:
+		return ""
+	}
+	if dir, ok := lookup(envVar); ok && filepath.IsAbs(dir) {
+		return filepath.Join(dir, ap.Name)
+	}
+	if runtime.GOOS == "windows" {
+		if dir, ok := lookup(winEnvVar); ok && filepath.IsAbs(dir) {
+			return filepath.Join(dir, ap.Name)
+		}
+	}
+	home := userHomeDir(lookup)
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(append(append([]string{home}, fallback...), ap.Name)...)
+}
+
+// ConfigDir returns the application config directory (e.g. `~/.config/<name>`). See `App.UserDir`.
+func (ap *App) ConfigDir() string {
+	return ap.UserDir(DirConfig, ap.lookupEnv())
+}
+
+// CacheDir returns the application cache directory (e.g. `~/.cache/<name>`). See `App.UserDir`.
+func (ap *App) CacheDir() string {
+	return ap.UserDir(DirCache, ap.lookupEnv())
+}
+
+// DataDir returns the application data directory (e.g. `~/.local/share/<name>`). See `App.UserDir`.
+func (ap *App) DataDir() string {
+	return ap.UserDir(DirData, ap.lookupEnv())
+}
+
+// StateDir returns the application state directory (e.g. `~/.local/state/<name>`). See `App.UserDir`.
+func (ap *App) StateDir() string {
+	return ap.UserDir(DirState, ap.lookupEnv())
+}
+
+// lookupEnv returns the application environment lookup function or nil if not set.
+func (ap *App) lookupEnv() flag.LookupEnvFunc {
+	if ap == nil {
+		return nil
+	}
+	return ap.LookupEnv
+}
+
+// userHomeDir returns the home directory via a given lookup function falling back to `os.UserHomeDir`.
+func userHomeDir(lookup flag.LookupEnvFunc) string {
+	if home, ok := lookup("HOME"); ok && home != "" {
+		return home
+	}
+	if runtime.GOOS == "windows" {
+		if home, ok := lookup("USERPROFILE"); ok && home != "" {
+			return home
+		}
+	}
+	home, _ := os.UserHomeDir()
+	return home
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/flag"
+)
+
+func TestApp_UserDir(t *testing.T) {
+	home := filepath.Join(t.TempDir(), "home")
+	xdg := filepath.Join(t.TempDir(), "xdg")
+	ap := &App{Name: "tool"}
+
+	t.Run("should use the XDG environment variables", func(t *testing.T) {
+		lookup := flag.LookupEnvMap(map[string]string{
+			"HOME":            home,
+			"XDG_CONFIG_HOME": filepath.Join(xdg, "config"),
+			"XDG_CACHE_HOME":  filepath.Join(xdg, "cache"),
+			"XDG_DATA_HOME":   filepath.Join(xdg, "data"),
+			"XDG_STATE_HOME":  filepath.Join(xdg, "state"),
+		})
+		assert.Equal(t, filepath.Join(xdg, "config", "tool"), ap.UserDir(DirConfig, lookup))
+		assert.Equal(t, filepath.Join(xdg, "cache", "tool"), ap.UserDir(DirCache, lookup))
+		assert.Equal(t, filepath.Join(xdg, "data", "tool"), ap.UserDir(DirData, lookup))
+		assert.Equal(t, filepath.Join(xdg, "state", "tool"), ap.UserDir(DirState, lookup))
+	})
+
+	t.Run("should fall back to the home directory", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("windows falls back to the application data directories")
+		}
+		lookup := flag.LookupEnvMap(map[string]string{"HOME": home, "XDG_CONFIG_HOME": "relative/config"})
+		assert.Equal(t, filepath.Join(home, ".config", "tool"), ap.UserDir(DirConfig, lookup))
+		assert.Equal(t, filepath.Join(home, ".cache", "tool"), ap.UserDir(DirCache, lookup))
+		assert.Equal(t, filepath.Join(home, ".local", "share", "tool"), ap.UserDir(DirData, lookup))
+		assert.Equal(t, filepath.Join(home, ".local", "state", "tool"), ap.UserDir(DirState, lookup))
+	})
+
+	t.Run("should return an empty directory without application name", func(t *testing.T) {
+		lookup := flag.LookupEnvMap(map[string]string{"HOME": home})
+		assert.Equal(t, "", (&App{}).UserDir(DirConfig, lookup))
+		assert.Equal(t, "", ap.UserDir(DirKind(-1), lookup))
+	})
+}
+
+func TestAppContext_Dirs(t *testing.T) {
+	xdg := filepath.Join(t.TempDir(), "xdg")
+	ap := &App{Name: "tool", LookupEnv: flag.LookupEnvMap(map[string]string{
+		"XDG_CONFIG_HOME": filepath.Join(xdg, "config"),
+		"XDG_CACHE_HOME":  filepath.Join(xdg, "cache"),
+		"XDG_DATA_HOME":   filepath.Join(xdg, "data"),
+		"XDG_STATE_HOME":  filepath.Join(xdg, "state"),
+	})}
+	ctx := NewContext(ap, nil, nil)
+
+	assert.Equal(t, filepath.Join(xdg, "config", "tool"), ctx.ConfigDir())
+	assert.Equal(t, filepath.Join(xdg, "cache", "tool"), ctx.CacheDir())
+	assert.Equal(t, filepath.Join(xdg, "data", "tool"), ctx.DataDir())
+	assert.Equal(t, filepath.Join(xdg, "state", "tool"), ctx.StateDir())
+}
+
+func TestAppContext_WithLookupEnv(t *testing.T) {
+	xdg := filepath.Join(t.TempDir(), "xdg")
+	ap := &App{Name: "tool", LookupEnv: flag.LookupEnvMap(map[string]string{
+		"XDG_CONFIG_HOME": filepath.Join(xdg, "app"),
+	})}
+	ctx := NewContext(ap, nil, nil).WithLookupEnv(flag.LookupEnvMap(map[string]string{
+		"XDG_CONFIG_HOME": filepath.Join(xdg, "resolved"),
+	}))
+
+	assert.Equal(t, filepath.Join(xdg, "resolved", "tool"), ctx.ConfigDir())
+}
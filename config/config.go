@@ -0,0 +1,115 @@
+// Package config provides config file loading for flag values.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Decoder decodes the contents of a config file into a map of keys and values.
+// Nested maps represent command sections.
+type Decoder func(data []byte) (map[string]any, error)
+
+// DecodeJSON decodes the contents of a JSON config file.
+// Numbers are decoded as `json.Number` to preserve their precision.
+func DecodeJSON(data []byte) (map[string]any, error) {
+	values := map[string]any{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Load reads and decodes a given config file using the decoder associated to its file extension.
+// The built-in JSON decoder (`.json`) can be replaced or completed with other ones (e.g. `.toml` or `.yaml`).
+func Load(path string, decoders map[string]Decoder) (map[string]any, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	decode, ok := decoders[ext]
+	if !ok && ext == ".json" {
+		decode, ok = DecodeJSON, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("error: unsupported config file format '%s' of file '%s'", ext, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error: unable to read config file '%s': %w", path, err)
+	}
+	values, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("error: unable to decode config file '%s': %w", path, err)
+	}
+	return values, nil
+}
+
+// Find returns the first existing file of the given paths or an empty string if none exists.
+func Find(paths []string) string {
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		if info, err := os.Stat(p); err == nil && !info.IsDir() {
+			return p
+		}
+	}
+	return ""
+}
+
+// Lookup returns the value of a given flag name.
+// Keys can also use underscores instead of dashes (e.g. `log_level` for `log-level`).
+func Lookup(values map[string]any, name string) (any, bool) {
+	if v, ok := values[name]; ok {
+		return v, true
+	}
+	v, ok := values[strings.ReplaceAll(name, "-", "_")]
+	return v, ok
+}
+
+// Section returns the values of a given command section (if any).
+func Section(values map[string]any, name string) map[string]any {
+	v, _ := Lookup(values, name)
+	section, _ := v.(map[string]any)
+	return section
+}
+
+// ToString converts a given config value into a raw flag value.
+// Lists are joined with commas like string slice flag values.
+func ToString(v any) (string, error) {
+	switch s := v.(type) {
+	case string:
+		return s, nil
+	case bool:
+		return strconv.FormatBool(s), nil
+	case json.Number:
+		return s.String(), nil
+	case int:
+		return strconv.Itoa(s), nil
+	case int64:
+		return strconv.FormatInt(s, 10), nil
+	case uint64:
+		return strconv.FormatUint(s, 10), nil
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64), nil
+	case []string:
+		return strings.Join(s, ","), nil
+	case []any:
+		items := make([]string, 0, len(s))
+		for _, item := range s {
+			str, err := ToString(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, str)
+		}
+		return strings.Join(items, ","), nil
+	}
+	return "", fmt.Errorf("unsupported value type %T", v)
+}
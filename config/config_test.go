@@ -0,0 +1,114 @@
+package config_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/config"
+)
+
+func writeFile(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := writeFile(t, dir, "app.JSON", `{"port": 8080, "name": "x", "info": {"trace": true}}`)
+	iniPath := writeFile(t, dir, "app.ini", "port=8080\n")
+	invalidPath := writeFile(t, dir, "invalid.json", `{"port": `)
+
+	t.Run("should decode json files", func(t *testing.T) {
+		values, err := config.Load(jsonPath, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"port": json.Number("8080"),
+			"name": "x",
+			"info": map[string]any{"trace": true},
+		}, values)
+	})
+
+	t.Run("should decode files via custom decoders", func(t *testing.T) {
+		decoders := map[string]config.Decoder{
+			".ini": func(data []byte) (map[string]any, error) {
+				k, v, _ := strings.Cut(strings.TrimSpace(string(data)), "=")
+				return map[string]any{k: v}, nil
+			},
+		}
+		values, err := config.Load(iniPath, decoders)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"port": "8080"}, values)
+	})
+
+	t.Run("should report unsupported formats", func(t *testing.T) {
+		_, err := config.Load(iniPath, nil)
+		assert.EqualError(t, err, "error: unsupported config file format '.ini' of file '"+iniPath+"'")
+	})
+
+	t.Run("should report decoding errors", func(t *testing.T) {
+		_, err := config.Load(invalidPath, nil)
+		assert.ErrorContains(t, err, "error: unable to decode config file '"+invalidPath+"'")
+	})
+
+	t.Run("should report reading errors", func(t *testing.T) {
+		_, err := config.Load(filepath.Join(dir, "none.json"), nil)
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+	})
+}
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "app.json", "{}")
+	assert.Equal(t, path, config.Find([]string{"", filepath.Join(dir, "none.json"), dir, path}))
+	assert.Equal(t, "", config.Find([]string{filepath.Join(dir, "none.json")}))
+}
+
+func TestLookup(t *testing.T) {
+	values := map[string]any{"log_level": "debug", "info": map[string]any{"trace": true}}
+
+	v, ok := config.Lookup(values, "log-level")
+	assert.True(t, ok)
+	assert.Equal(t, "debug", v)
+
+	_, ok = config.Lookup(values, "port")
+	assert.False(t, ok)
+
+	assert.Equal(t, map[string]any{"trace": true}, config.Section(values, "info"))
+	assert.Nil(t, config.Section(values, "log-level"))
+}
+
+func TestToString(t *testing.T) {
+	tests := []struct {
+		value   any
+		want    string
+		wantErr string
+	}{
+		{value: "a", want: "a"},
+		{value: true, want: "true"},
+		{value: json.Number("42"), want: "42"},
+		{value: int64(-7), want: "-7"},
+		{value: 1.5, want: "1.5"},
+		{value: []string{"a", "b"}, want: "a,b"},
+		{value: []any{"a", 1, false}, want: "a,1,false"},
+		{value: map[string]any{}, wantErr: "unsupported value type map[string]interface {}"},
+	}
+	for _, tt := range tests {
+		s, err := config.ToString(tt.value)
+		if tt.wantErr != "" {
+			assert.EqualError(t, err, tt.wantErr)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, s)
+	}
+}
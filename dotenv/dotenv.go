@@ -0,0 +1,245 @@
+// Package dotenv provides dotenv (`.env`) files loading.
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+// SyntaxError defines a dotenv syntax error at a given line.
+type SyntaxError struct {
+	// The line number (starting at 1).
+	Line int
+	// The error message.
+	Message string
+}
+
+// Error returns the error message.
+func (e *SyntaxError) Error() string {
+	return e.Message
+}
+
+// syntaxError creates a new syntax error at a given line.
+func syntaxError(line int, format string, a ...any) *SyntaxError {
+	return &SyntaxError{Line: line, Message: fmt.Sprintf(format, a...)}
+}
+
+// Parse parses the contents of a dotenv file into a map of variables.
+// Lines contain `KEY=value` pairs with an optional `export` prefix and `#` comments.
+// Values can be single quoted (literal), double quoted (escapes, interpolation and multiple lines) or unquoted
+// (interpolation and inline comments). Interpolation (`${VAR}`) uses the file variables defined previously
+// and then a given lookup function. Errors are `*SyntaxError` values containing the line number involved.
+func Parse(data string, lookup flag.LookupEnvFunc) (map[string]string, error) {
+	return parse(data, lookup, true)
+}
+
+// parse is like Parse but the file variables take precedence over the lookup function ones only when override is true.
+func parse(data string, lookup flag.LookupEnvFunc, override bool) (map[string]string, error) {
+	vars := map[string]string{}
+	if lookup == nil {
+		lookup = func(string) (string, bool) { return "", false }
+	}
+	get := func(key string) (string, bool) {
+		if !override {
+			if v, ok := lookup(key); ok {
+				return v, true
+			}
+		}
+		if v, ok := vars[key]; ok {
+			return v, true
+		}
+		return lookup(key)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	for n := 0; n < len(lines); n++ {
+		lineNum := n + 1
+		line := strings.TrimSpace(lines[n])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "export"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			line = strings.TrimSpace(rest)
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, syntaxError(lineNum, "missing '=' separator")
+		}
+		key = strings.TrimSpace(key)
+		if !isValidKey(key) {
+			return nil, syntaxError(lineNum, "invalid variable name '%s'", key)
+		}
+		value = strings.TrimSpace(value)
+
+		switch {
+		case strings.HasPrefix(value, "'"):
+			// Single quoted values are literal
+			quoted, rest, ok := cutQuoted(value[1:], '\'', lines, &n)
+			if !ok {
+				return nil, syntaxError(lineNum, "unterminated single quote")
+			}
+			if !isBlankOrComment(rest) {
+				return nil, syntaxError(lineNum, "unexpected characters after the quoted value")
+			}
+			value = quoted
+		case strings.HasPrefix(value, `"`):
+			// Double quoted values support escapes and interpolation
+			quoted, rest, ok := cutQuoted(value[1:], '"', lines, &n)
+			if !ok {
+				return nil, syntaxError(lineNum, "unterminated double quote")
+			}
+			if !isBlankOrComment(rest) {
+				return nil, syntaxError(lineNum, "unexpected characters after the quoted value")
+			}
+			value = helpers.ExpandEnv(unescape(quoted), get)
+		default:
+			// Unquoted values support inline comments and interpolation
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = value[:i]
+			}
+			if i := strings.Index(value, "\t#"); i >= 0 {
+				value = value[:i]
+			}
+			value = helpers.ExpandEnv(strings.TrimSpace(value), get)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// Load loads the variables of the given dotenv files in order skipping the ones which do not exist.
+// When override is false, the variables of the first files and the lookup function (the process environment if nil)
+// take precedence. Otherwise the variables of the last files take precedence over the previous ones.
+func Load(paths []string, override bool, lookup flag.LookupEnvFunc) (map[string]string, error) {
+	vars := map[string]string{}
+	get := LookupFunc(vars, override, lookup)
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("error: unable to read env file '%s': %w", path, err)
+		}
+		fileVars, err := parse(string(data), get, override)
+		if err != nil {
+			var serr *SyntaxError
+			if errors.As(err, &serr) {
+				return nil, fmt.Errorf("error: %s:%d: %w", path, serr.Line, err)
+			}
+			return nil, err
+		}
+		for k, v := range fileVars {
+			if _, exists := vars[k]; exists && !override {
+				continue
+			}
+			vars[k] = v
+		}
+	}
+	return vars, nil
+}
+
+// LookupFunc returns a lookup function combining a given map of variables and a lookup function
+// (the process environment if nil). When override is true, the map variables take precedence.
+func LookupFunc(vars map[string]string, override bool, lookup flag.LookupEnvFunc) flag.LookupEnvFunc {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	return func(key string) (string, bool) {
+		if !override {
+			if v, ok := lookup(key); ok {
+				return v, true
+			}
+		}
+		if v, ok := vars[key]; ok {
+			return v, true
+		}
+		if override {
+			return lookup(key)
+		}
+		return "", false
+	}
+}
+
+// cutQuoted returns the text until a given closing quote (ignoring escaped double quotes)
+// and the text after it. It continues on the next lines (joined with new lines) when the quote is not closed.
+func cutQuoted(s string, quote byte, lines []string, n *int) (quoted string, rest string, ok bool) {
+	var b strings.Builder
+	for {
+		for i := 0; i < len(s); i++ {
+			if quote == '"' && s[i] == '\\' && i+1 < len(s) {
+				b.WriteByte(s[i])
+				b.WriteByte(s[i+1])
+				i++
+				continue
+			}
+			if s[i] == quote {
+				return b.String(), s[i+1:], true
+			}
+			b.WriteByte(s[i])
+		}
+		if *n+1 >= len(lines) {
+			return "", "", false
+		}
+		*n++
+		b.WriteByte('\n')
+		s = lines[*n]
+	}
+}
+
+// unescape replaces the escape sequences of a double quoted value.
+// Escaped dollars are kept as `$$` so they are not interpolated.
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '$':
+			b.WriteString("$$")
+		case '"', '\\':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// isBlankOrComment checks if a given text is blank or a comment.
+func isBlankOrComment(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == "" || strings.HasPrefix(s, "#")
+}
+
+// isValidKey checks if a given variable name contains only alphanumeric characters, underscores and dots
+// and does not start with a digit.
+func isValidKey(key string) bool {
+	if key == "" || (key[0] >= '0' && key[0] <= '9') {
+		return false
+	}
+	for _, c := range key {
+		if c != '_' && c != '.' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
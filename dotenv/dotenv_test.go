@@ -0,0 +1,121 @@
+package dotenv_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/dotenv"
+)
+
+func TestParse(t *testing.T) {
+	env := map[string]string{"HOME": "/home/me"}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	t.Run("should parse variables", func(t *testing.T) {
+		data := "# comment\r\n" +
+			"\n" +
+			"PLAIN=value # inline comment\n" +
+			"export EXPORTED = spaced value \n" +
+			"SINGLE='literal $HOME # not a comment'\n" +
+			"DOUBLE=\"tab\\tquote\\\" \\$HOME ${HOME}\" # comment\n" +
+			"MULTI=\"first\n" +
+			"second\"\n" +
+			"REF=${PLAIN}-${UNSET:-def}\n" +
+			"EMPTY=\n" +
+			"exported=1\n"
+		vars, err := dotenv.Parse(data, lookup)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"PLAIN":    "value",
+			"EXPORTED": "spaced value",
+			"SINGLE":   "literal $HOME # not a comment",
+			"DOUBLE":   "tab\tquote\" $HOME /home/me",
+			"MULTI":    "first\nsecond",
+			"REF":      "value-def",
+			"EMPTY":    "",
+			"exported": "1",
+		}, vars)
+	})
+
+	t.Run("should report syntax errors with their lines", func(t *testing.T) {
+		tests := []struct {
+			data    string
+			line    int
+			message string
+		}{
+			{data: "A=1\nINVALID\n", line: 2, message: "missing '=' separator"},
+			{data: "1A=1", line: 1, message: "invalid variable name '1A'"},
+			{data: "A='x", line: 1, message: "unterminated single quote"},
+			{data: "A=1\nB=\"x\ny", line: 2, message: "unterminated double quote"},
+			{data: "A='x' y", line: 1, message: "unexpected characters after the quoted value"},
+		}
+		for _, tt := range tests {
+			_, err := dotenv.Parse(tt.data, lookup)
+			var serr *dotenv.SyntaxError
+			if assert.True(t, errors.As(err, &serr), tt.data) {
+				assert.Equal(t, tt.line, serr.Line)
+				assert.EqualError(t, err, tt.message)
+			}
+		}
+	})
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	first := write(".env", "A=first\nB=${EXISTING}-b\n")
+	second := write(".env.local", "A=second\nC=${A}\nEXISTING=dotenv\n")
+	invalid := write(".env.invalid", "A=1\nB\n")
+	missing := filepath.Join(dir, ".env.missing")
+
+	env := map[string]string{"EXISTING": "process"}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	t.Run("should not overwrite existing variables by default", func(t *testing.T) {
+		vars, err := dotenv.Load([]string{first, missing, second}, false, lookup)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"A": "first", "B": "process-b", "C": "first", "EXISTING": "dotenv"}, vars)
+
+		get := dotenv.LookupFunc(vars, false, lookup)
+		v, _ := get("EXISTING")
+		assert.Equal(t, "process", v)
+		v, _ = get("A")
+		assert.Equal(t, "first", v)
+		_, ok := get("UNSET")
+		assert.False(t, ok)
+	})
+
+	t.Run("should overlay variables when overriding", func(t *testing.T) {
+		vars, err := dotenv.Load([]string{first, second}, true, lookup)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"A": "second", "B": "process-b", "C": "second", "EXISTING": "dotenv"}, vars)
+
+		get := dotenv.LookupFunc(vars, true, lookup)
+		v, _ := get("EXISTING")
+		assert.Equal(t, "dotenv", v)
+	})
+
+	t.Run("should report errors with the file and line", func(t *testing.T) {
+		_, err := dotenv.Load([]string{first, invalid}, false, lookup)
+		assert.EqualError(t, err, "error: "+invalid+":2: missing '=' separator")
+
+		_, err = dotenv.Load([]string{dir}, false, lookup)
+		assert.ErrorContains(t, err, "error: unable to read env file '"+dir+"'")
+	})
+}
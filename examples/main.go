@@ -140,8 +140,5 @@ func main() {
 		return nil
 	}
 
-	if err := handler.New(ap).Run(os.Args); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
-	}
+	os.Exit(handler.New(ap).Main(os.Args))
 }
@@ -0,0 +1,56 @@
+package flag
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// EnvError defines an environment variable value which cannot be converted into its flag type.
+type EnvError struct {
+	// The flag name.
+	Flag string
+	// The environment variable name.
+	EnvVar string
+	// The invalid environment variable value.
+	Value string
+	// The underlying conversion error.
+	Err error
+}
+
+// Error returns the error message.
+func (e *EnvError) Error() string {
+	return fmt.Sprintf("invalid value '%s' of environment variable '%s' for flag '--%s'", e.Value, e.EnvVar, e.Flag)
+}
+
+// Unwrap returns the underlying conversion error.
+func (e *EnvError) Unwrap() error {
+	return e.Err
+}
+
+// CheckEnv reports an `EnvError` when the first environment variable set is not a valid integer.
+// A nil function uses the process environment.
+func (fi *FlagInt) CheckEnv(lookup LookupEnvFunc) error {
+	if lookup == nil {
+		lookup = syscall.Getenv
+	}
+	if name, ev, ok := lookupFirst(lookup, fi.EnvVar, fi.EnvVars); ok {
+		if _, err := Value(ev).ToInt(); err != nil {
+			return &EnvError{Flag: fi.Name, EnvVar: name, Value: ev, Err: err}
+		}
+	}
+	return nil
+}
+
+// CheckEnv reports an `EnvError` when the first environment variable set is not a valid boolean.
+// A nil function uses the process environment.
+func (fb *FlagBool) CheckEnv(lookup LookupEnvFunc) error {
+	if lookup == nil {
+		lookup = syscall.Getenv
+	}
+	if name, ev, ok := lookupFirst(lookup, fb.EnvVar, fb.EnvVars); ok {
+		if _, err := Value(ev).ToBool(); err != nil {
+			return &EnvError{Flag: fb.Name, EnvVar: name, Value: ev, Err: err}
+		}
+	}
+	return nil
+}
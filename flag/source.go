@@ -0,0 +1,58 @@
+package flag
+
+import "fmt"
+
+// SourceKind defines the kind of source a flag value comes from.
+type SourceKind int
+
+const (
+	// SourceDefault reports a flag value coming from its declared default value.
+	SourceDefault SourceKind = iota
+	// SourceEnv reports a flag value coming from its environment variable.
+	SourceEnv
+	// SourceConfig reports a flag value coming from a config file.
+	SourceConfig
+	// SourceArg reports a flag value coming from the command line arguments.
+	SourceArg
+)
+
+// String returns the name of the source kind.
+func (k SourceKind) String() string {
+	switch k {
+	case SourceDefault:
+		return "default"
+	case SourceEnv:
+		return "env"
+	case SourceConfig:
+		return "config"
+	case SourceArg:
+		return "arg"
+	default:
+		return "unknown"
+	}
+}
+
+// Source describes where a flag value comes from.
+type Source struct {
+	// The kind of source.
+	Kind SourceKind
+	// The environment variable name of an env source.
+	EnvVar string
+	// The config file path of a config source or the file path of a secret value.
+	File string
+	// The argument index (`argv`) of the flag of an arg source.
+	Index int
+}
+
+// String returns a human readable description of the source (e.g. `env TOOL_HOST` or `arg 2`).
+func (s Source) String() string {
+	switch s.Kind {
+	case SourceEnv:
+		return fmt.Sprintf("%s %s", s.Kind, s.EnvVar)
+	case SourceConfig:
+		return fmt.Sprintf("%s %s", s.Kind, s.File)
+	case SourceArg:
+		return fmt.Sprintf("%s %d", s.Kind, s.Index)
+	}
+	return s.Kind.String()
+}
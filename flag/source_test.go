@@ -0,0 +1,33 @@
+package flag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/flag"
+)
+
+func TestSource_String(t *testing.T) {
+	assert.Equal(t, "default", flag.Source{}.String())
+	assert.Equal(t, "env TOOL_HOST", flag.Source{Kind: flag.SourceEnv, EnvVar: "TOOL_HOST"}.String())
+	assert.Equal(t, "config tool.json", flag.Source{Kind: flag.SourceConfig, File: "tool.json"}.String())
+	assert.Equal(t, "arg 2", flag.Source{Kind: flag.SourceArg, Index: 2}.String())
+	assert.Equal(t, "unknown", flag.SourceKind(-1).String())
+}
+
+func TestFlagValue_Source(t *testing.T) {
+	src := flag.Source{Kind: flag.SourceArg, Index: 1}
+
+	b := flag.ValueBool{Flag: flag.FlagBool{FlagSource: src}}
+	assert.Equal(t, src, b.Source())
+
+	i := flag.ValueInt{Flag: flag.FlagInt{FlagSource: src}}
+	assert.Equal(t, src, i.Source())
+
+	s := flag.ValueString{Flag: flag.FlagString{FlagSource: src}}
+	assert.Equal(t, src, s.Source())
+
+	ss := flag.ValueStringSlice{Flag: flag.FlagStringSlice{FlagSource: src}}
+	assert.Equal(t, src, ss.Source())
+}
@@ -1,6 +1,7 @@
 package flag
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"syscall"
@@ -9,6 +10,35 @@ import (
 // Flag defines a flag generic type.
 type Flag any
 
+// SecretMask replaces the secret flag values in the help, print config and `fmt` output.
+const SecretMask = "******"
+
+// LookupEnvFunc defines a function which looks up an environment variable like `os.LookupEnv`.
+type LookupEnvFunc func(key string) (string, bool)
+
+// lookupFirst looks up the given environment variables in order and returns the first one set.
+func lookupFirst(lookup LookupEnvFunc, envVar string, envVars []string) (name string, value string, ok bool) {
+	if envVar != "" {
+		if value, ok = lookup(envVar); ok {
+			return envVar, value, true
+		}
+	}
+	for _, name = range envVars {
+		if value, ok = lookup(name); ok {
+			return name, value, true
+		}
+	}
+	return "", "", false
+}
+
+// LookupEnvMap returns a function which looks up environment variables in a given map.
+func LookupEnvMap(env map[string]string) LookupEnvFunc {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
 // FlagInt defines an `Int` type flag.
 type FlagInt struct {
 	// Name of the flag containing alphanumeric characters and dashes
@@ -24,24 +54,40 @@ type FlagInt struct {
 	// An optional environment variable containing uppercase alphanumeric characters
 	// and underscores but without dashes, spaces or any kind of special chars.
 	EnvVar string
+	// Optional additional environment variables looked up in order after `EnvVar` (the first one set wins).
+	EnvVars []string
 
-	FlagValue           Value
-	FlagAssigned        bool
-	FlagProvided        bool
-	FlagProvidedAsAlias bool
+	FlagValue            Value
+	FlagAssigned         bool
+	FlagProvided         bool
+	FlagProvidedAsAlias  bool
+	FlagProvidedAsAbbrev bool
+	FlagSource           Source
 }
 
 // Init sets a default flag value via its associated `Value` prop
-// or its environment variable (`EnvVar`) if so.
+// or its environment variables (`EnvVar` or `EnvVars`) if so.
 func (fi *FlagInt) Init() {
+	fi.InitEnv(nil)
+}
+
+// InitEnv is like Init but it looks up the environment variable via a given function.
+// A nil function uses the process environment.
+func (fi *FlagInt) InitEnv(lookup LookupEnvFunc) {
+	if lookup == nil {
+		lookup = syscall.Getenv
+	}
 	val := Value(strconv.Itoa(fi.Value))
-	if ev, ok := syscall.Getenv(fi.EnvVar); ok {
+	src := Source{}
+	if name, ev, ok := lookupFirst(lookup, fi.EnvVar, fi.EnvVars); ok {
 		s := Value(ev)
 		if _, err := s.ToInt(); err == nil {
 			val = s
+			src = Source{Kind: SourceEnv, EnvVar: name}
 		}
 	}
 	fi.FlagValue = val
+	fi.FlagSource = src
 }
 
 // FlagBool defines a `bool` type flag.
@@ -59,23 +105,39 @@ type FlagBool struct {
 	// An optional environment variable containing uppercase alphanumeric characters
 	// and underscores but without dashes, spaces or any kind of special chars.
 	EnvVar string
+	// Optional additional environment variables looked up in order after `EnvVar` (the first one set wins).
+	EnvVars []string
 
-	FlagValue           Value
-	FlagAssigned        bool
-	FlagProvided        bool
-	FlagProvidedAsAlias bool
+	FlagValue            Value
+	FlagAssigned         bool
+	FlagProvided         bool
+	FlagProvidedAsAlias  bool
+	FlagProvidedAsAbbrev bool
+	FlagSource           Source
 }
 
 // Init sets a default flag value via its associated `Value` prop
-// or its environment variable (`EnvVar`) if so.
+// or its environment variables (`EnvVar` or `EnvVars`) if so.
 func (fb *FlagBool) Init() {
+	fb.InitEnv(nil)
+}
+
+// InitEnv is like Init but it looks up the environment variable via a given function.
+// A nil function uses the process environment.
+func (fb *FlagBool) InitEnv(lookup LookupEnvFunc) {
+	if lookup == nil {
+		lookup = syscall.Getenv
+	}
 	val := Value(strconv.FormatBool(fb.Value))
-	if ev, ok := syscall.Getenv(fb.EnvVar); ok {
+	src := Source{}
+	if name, ev, ok := lookupFirst(lookup, fb.EnvVar, fb.EnvVars); ok {
 		if b, err := Value(ev).ToBool(); err == nil {
 			val = Value(strconv.FormatBool(b))
+			src = Source{Kind: SourceEnv, EnvVar: name}
 		}
 	}
 	fb.FlagValue = val
+	fb.FlagSource = src
 }
 
 // FlagString defines a `String` type flag.
@@ -93,21 +155,69 @@ type FlagString struct {
 	// An optional environment variable containing uppercase alphanumeric characters
 	// and underscores but without dashes, spaces or any kind of special chars.
 	EnvVar string
+	// Optional additional environment variables looked up in order after `EnvVar` (the first one set wins).
+	EnvVars []string
 
-	FlagValue           Value
-	FlagAssigned        bool
-	FlagProvided        bool
-	FlagProvidedAsAlias bool
+	// Treat the value as a secret which is masked in the help, print config and `fmt` output.
+	// It can also be read from a file via a `--<name>-file` flag or a `<ENV>_FILE` environment variable,
+	// or from the standard input via a `-` value. Trailing newlines of the read values are trimmed.
+	Secret bool
+
+	FlagValue            Value
+	FlagAssigned         bool
+	FlagProvided         bool
+	FlagProvidedAsAlias  bool
+	FlagProvidedAsAbbrev bool
+	FlagSource           Source
 }
 
 // Init sets a default flag value via its associated `Value` prop
-// or its environment variable (`EnvVar`) if so.
+// or its environment variables (`EnvVar` or `EnvVars`) if so.
 func (fs *FlagString) Init() {
+	fs.InitEnv(nil)
+}
+
+// InitEnv is like Init but it looks up the environment variable via a given function.
+// A nil function uses the process environment.
+func (fs *FlagString) InitEnv(lookup LookupEnvFunc) {
+	if lookup == nil {
+		lookup = syscall.Getenv
+	}
 	val := Value(fs.Value)
-	if ev, ok := syscall.Getenv(fs.EnvVar); ok {
+	src := Source{}
+	if name, ev, ok := lookupFirst(lookup, fs.EnvVar, fs.EnvVars); ok {
 		val = Value(ev)
+		src = Source{Kind: SourceEnv, EnvVar: name}
 	}
 	fs.FlagValue = val
+	fs.FlagSource = src
+}
+
+// MaskedValue returns the flag value or `SecretMask` if it is a non-empty secret value.
+func (fs FlagString) MaskedValue() string {
+	if fs.Secret && fs.FlagValue != "" {
+		return SecretMask
+	}
+	return fs.FlagValue.ToString()
+}
+
+// flagString is a `FlagString` without its own formatting methods.
+type flagString FlagString
+
+// Format implements `fmt.Formatter` so the value of a secret flag is replaced by `SecretMask`
+// when the flag is printed via `fmt` verbs like `%v`, `%+v` or `%#v`.
+func (fs FlagString) Format(f fmt.State, verb rune) {
+	if fs.Secret {
+		if fs.Value != "" {
+			fs.Value = SecretMask
+		}
+		fs.FlagValue = Value(fs.MaskedValue())
+	}
+	s := fmt.Sprintf(fmt.FormatString(f, verb), flagString(fs))
+	if f.Flag('#') {
+		s = strings.Replace(s, "flag.flagString{", "flag.FlagString{", 1)
+	}
+	_, _ = f.Write([]byte(s))
 }
 
 // FlagStringSlice defines a string slice type flag.
@@ -125,19 +235,97 @@ type FlagStringSlice struct {
 	// An optional environment variable containing uppercase alphanumeric characters
 	// and underscores but without dashes, spaces or any kind of special chars.
 	EnvVar string
+	// Optional additional environment variables looked up in order after `EnvVar` (the first one set wins).
+	EnvVars []string
 
-	FlagValue           Value
-	FlagAssigned        bool
-	FlagProvided        bool
-	FlagProvidedAsAlias bool
+	FlagValue            Value
+	FlagAssigned         bool
+	FlagProvided         bool
+	FlagProvidedAsAlias  bool
+	FlagProvidedAsAbbrev bool
+	FlagSource           Source
 }
 
 // Init sets a default flag value via its associated `Value` prop
-// or its environment variable (`EnvVar`) if so.
+// or its environment variables (`EnvVar` or `EnvVars`) if so.
 func (fs *FlagStringSlice) Init() {
+	fs.InitEnv(nil)
+}
+
+// InitEnv is like Init but it looks up the environment variable via a given function.
+// A nil function uses the process environment.
+func (fs *FlagStringSlice) InitEnv(lookup LookupEnvFunc) {
+	if lookup == nil {
+		lookup = syscall.Getenv
+	}
 	val := Value(strings.Join(fs.Value, ","))
-	if ev, ok := syscall.Getenv(fs.EnvVar); ok {
+	src := Source{}
+	if name, ev, ok := lookupFirst(lookup, fs.EnvVar, fs.EnvVars); ok {
 		val = Value(ev)
+		src = Source{Kind: SourceEnv, EnvVar: name}
 	}
 	fs.FlagValue = val
+	fs.FlagSource = src
+}
+
+// FlagPath defines a file or directory path type flag.
+// Its value expands a leading `~` and `$VAR` or `${VAR}` references and it can be checked declaratively.
+type FlagPath struct {
+	// Name of the flag containing alphanumeric characters and dashes
+	// but without leading dashes, spaces or any kind of special chars.
+	Name string
+	// An optional summary for the flag.
+	Summary string
+	// An optional default value for the flag.
+	Value string
+	// An optional list of flag aliases containing single alphanumeric characters
+	// but without dashes, spaces or any special chars.
+	Aliases []string
+	// An optional environment variable containing uppercase alphanumeric characters
+	// and underscores but without dashes, spaces or any kind of special chars.
+	EnvVar string
+	// Optional additional environment variables looked up in order after `EnvVar` (the first one set wins).
+	EnvVars []string
+	// Resolve the path into an absolute one.
+	Absolute bool
+	// The path must exist.
+	MustExist bool
+	// The path must be a file if it exists.
+	MustBeFile bool
+	// The path must be a directory if it exists.
+	MustBeDir bool
+	// The path must be writable or, if it does not exist, its parent directory.
+	// Directories are checked while parsing by creating and removing a temporary file inside them.
+	MustBeWritable bool
+	// The parent directory of the path must exist.
+	ParentMustExist bool
+
+	FlagValue            Value
+	FlagAssigned         bool
+	FlagProvided         bool
+	FlagProvidedAsAlias  bool
+	FlagProvidedAsAbbrev bool
+	FlagSource           Source
+}
+
+// Init sets a default flag value via its associated `Value` prop
+// or its environment variables (`EnvVar` or `EnvVars`) if so.
+func (fp *FlagPath) Init() {
+	fp.InitEnv(nil)
+}
+
+// InitEnv is like Init but it looks up the environment variable via a given function.
+// A nil function uses the process environment.
+func (fp *FlagPath) InitEnv(lookup LookupEnvFunc) {
+	if lookup == nil {
+		lookup = syscall.Getenv
+	}
+	val := Value(fp.Value)
+	src := Source{}
+	if name, ev, ok := lookupFirst(lookup, fp.EnvVar, fp.EnvVars); ok {
+		val = Value(ev)
+		src = Source{Kind: SourceEnv, EnvVar: name}
+	}
+	fp.FlagValue = val
+	fp.FlagSource = src
 }
@@ -1,6 +1,7 @@
 package flag_test
 
 import (
+	"fmt"
 	"os"
 	"testing"
 
@@ -264,3 +265,141 @@ func TestFlagStringSlice_init(t *testing.T) {
 		})
 	}
 }
+
+func TestFlag_initEnv(t *testing.T) {
+	lookup := flag.LookupEnvMap(map[string]string{"INT": "7", "BOOL": "true", "STRING": "s", "SLICE": "a,b"})
+	t.Setenv("STRING", "process")
+
+	fi := &flag.FlagInt{Name: "i", EnvVar: "INT"}
+	fi.InitEnv(lookup)
+	assert.Equal(t, flag.Value("7"), fi.FlagValue)
+
+	fb := &flag.FlagBool{Name: "b", EnvVar: "BOOL"}
+	fb.InitEnv(lookup)
+	assert.Equal(t, flag.Value("true"), fb.FlagValue)
+
+	fs := &flag.FlagString{Name: "s", EnvVar: "STRING"}
+	fs.InitEnv(lookup)
+	assert.Equal(t, flag.Value("s"), fs.FlagValue)
+
+	fs.InitEnv(nil)
+	assert.Equal(t, flag.Value("process"), fs.FlagValue)
+
+	fss := &flag.FlagStringSlice{Name: "ss", EnvVar: "SLICE"}
+	fss.InitEnv(lookup)
+	assert.Equal(t, flag.Value("a,b"), fss.FlagValue)
+}
+
+func TestLookupEnvMap(t *testing.T) {
+	lookup := flag.LookupEnvMap(map[string]string{"A": "1", "EMPTY": ""})
+
+	v, ok := lookup("A")
+	assert.True(t, ok)
+	assert.Equal(t, "1", v)
+
+	_, ok = lookup("EMPTY")
+	assert.True(t, ok)
+
+	_, ok = lookup("B")
+	assert.False(t, ok)
+}
+
+func TestFlag_initEnvVars(t *testing.T) {
+	lookup := flag.LookupEnvMap(map[string]string{"NEW_LEVEL": "2", "OLD_LEVEL": "1", "LEGACY_ON": "true"})
+
+	fi := &flag.FlagInt{Name: "level", EnvVars: []string{"MISSING", "NEW_LEVEL", "OLD_LEVEL"}}
+	fi.InitEnv(lookup)
+	assert.Equal(t, flag.Value("2"), fi.FlagValue)
+	assert.Equal(t, flag.Source{Kind: flag.SourceEnv, EnvVar: "NEW_LEVEL"}, fi.FlagSource)
+
+	fi = &flag.FlagInt{Name: "level", EnvVar: "OLD_LEVEL", EnvVars: []string{"NEW_LEVEL"}}
+	fi.InitEnv(lookup)
+	assert.Equal(t, flag.Value("1"), fi.FlagValue)
+	assert.Equal(t, "OLD_LEVEL", fi.FlagSource.EnvVar)
+
+	fb := &flag.FlagBool{Name: "on", EnvVar: "ON", EnvVars: []string{"LEGACY_ON"}}
+	fb.InitEnv(lookup)
+	assert.Equal(t, flag.Value("true"), fb.FlagValue)
+	assert.Equal(t, "LEGACY_ON", fb.FlagSource.EnvVar)
+
+	fs := &flag.FlagString{Name: "s", Value: "def", EnvVars: []string{"UNSET"}}
+	fs.InitEnv(lookup)
+	assert.Equal(t, flag.Value("def"), fs.FlagValue)
+	assert.Equal(t, flag.Source{}, fs.FlagSource)
+}
+
+func TestFlag_CheckEnv(t *testing.T) {
+	lookup := flag.LookupEnvMap(map[string]string{"WORKERS": "ten", "DEBUG": "maybe", "PORT": "80", "ON": "1"})
+
+	fi := &flag.FlagInt{Name: "workers", EnvVar: "WORKERS"}
+	err := fi.CheckEnv(lookup)
+	assert.EqualError(t, err, "invalid value 'ten' of environment variable 'WORKERS' for flag '--workers'")
+	var envErr *flag.EnvError
+	assert.ErrorAs(t, err, &envErr)
+	assert.Equal(t, &flag.EnvError{Flag: "workers", EnvVar: "WORKERS", Value: "ten", Err: envErr.Err}, envErr)
+
+	fb := &flag.FlagBool{Name: "debug", EnvVars: []string{"UNSET", "DEBUG"}}
+	assert.EqualError(t, fb.CheckEnv(lookup), "invalid value 'maybe' of environment variable 'DEBUG' for flag '--debug'")
+
+	assert.NoError(t, (&flag.FlagInt{Name: "port", EnvVar: "PORT"}).CheckEnv(lookup))
+	assert.NoError(t, (&flag.FlagBool{Name: "on", EnvVar: "ON"}).CheckEnv(lookup))
+	assert.NoError(t, (&flag.FlagInt{Name: "unset", EnvVar: "UNSET"}).CheckEnv(lookup))
+}
+
+func TestFlagString_MaskedValue(t *testing.T) {
+	assert.Equal(t, flag.SecretMask, flag.FlagString{Secret: true, FlagValue: "s3cr3t"}.MaskedValue())
+	assert.Equal(t, "", flag.FlagString{Secret: true}.MaskedValue())
+	assert.Equal(t, "plain", flag.FlagString{FlagValue: "plain"}.MaskedValue())
+
+	v := &flag.ValueString{Flag: flag.FlagString{Secret: true, FlagValue: "s3cr3t"}}
+	assert.Equal(t, "s3cr3t", v.Value())
+	assert.Equal(t, flag.SecretMask, v.String())
+}
+
+func TestFlagString_Format(t *testing.T) {
+	tests := []struct {
+		format string
+		flag   flag.FlagString
+		want   []string
+		hidden string
+	}{
+		{
+			format: "%v",
+			flag:   flag.FlagString{Name: "token", Value: "d3f4ult", Secret: true, FlagValue: "s3cr3t", FlagProvided: true},
+			want:   []string{"{token", flag.SecretMask},
+			hidden: "s3cr3t",
+		},
+		{
+			format: "%+v",
+			flag:   flag.FlagString{Name: "token", Value: "d3f4ult", Secret: true, FlagValue: "s3cr3t"},
+			want:   []string{"Value:" + flag.SecretMask, "FlagValue:" + flag.SecretMask},
+			hidden: "d3f4ult",
+		},
+		{
+			format: "%#v",
+			flag:   flag.FlagString{Name: "token", Secret: true, FlagValue: "s3cr3t"},
+			want:   []string{"flag.FlagString{", `FlagValue:"` + flag.SecretMask + `"`},
+			hidden: "s3cr3t",
+		},
+		{
+			format: "%v",
+			flag:   flag.FlagString{Name: "token", Secret: true},
+			want:   []string{"{token"},
+			hidden: flag.SecretMask,
+		},
+		{
+			format: "%v",
+			flag:   flag.FlagString{Name: "name", FlagValue: "plain"},
+			want:   []string{"{name", "plain"},
+		},
+	}
+	for _, tt := range tests {
+		s := fmt.Sprintf(tt.format, []flag.Flag{tt.flag})
+		for _, w := range tt.want {
+			assert.Contains(t, s, w)
+		}
+		if tt.hidden != "" {
+			assert.NotContains(t, s, tt.hidden)
+		}
+	}
+}
@@ -59,6 +59,16 @@ func (v *ValueBool) IsProvidedLong() bool {
 	return v.Flag.FlagProvided && !v.Flag.FlagProvidedAsAlias
 }
 
+// IsProvidedAbbreviated checks if current `bool` flag was provided from stdin but using an abbreviation of its long name.
+func (v *ValueBool) IsProvidedAbbreviated() bool {
+	return v.Flag.FlagProvided && v.Flag.FlagProvidedAsAbbrev
+}
+
+// Source returns where current `bool` flag value comes from.
+func (v *ValueBool) Source() Source {
+	return v.Flag.FlagSource
+}
+
 // GetFlagType returns the associated flag type.
 func (v *ValueBool) GetFlagType() FlagBool {
 	return v.Flag
@@ -89,6 +99,16 @@ func (v *ValueInt) IsProvidedLong() bool {
 	return v.Flag.FlagProvided && !v.Flag.FlagProvidedAsAlias
 }
 
+// IsProvidedAbbreviated checks if current `int` flag was provided from stdin but using an abbreviation of its long name.
+func (v *ValueInt) IsProvidedAbbreviated() bool {
+	return v.Flag.FlagProvided && v.Flag.FlagProvidedAsAbbrev
+}
+
+// Source returns where current `int` flag value comes from.
+func (v *ValueInt) Source() Source {
+	return v.Flag.FlagSource
+}
+
 // GetFlagType returns the associated flag type.
 func (v *ValueInt) GetFlagType() FlagInt {
 	return v.Flag
@@ -104,6 +124,11 @@ func (v *ValueString) Value() string {
 	return v.Flag.FlagValue.ToString()
 }
 
+// String returns the plain `string` value of the current flag or a mask if it is a secret one.
+func (v *ValueString) String() string {
+	return v.Flag.MaskedValue()
+}
+
 // IsProvided checks if current `string` flag was provided from stdin.
 func (v *ValueString) IsProvided() bool {
 	return v.Flag.FlagProvided
@@ -119,6 +144,16 @@ func (v *ValueString) IsProvidedLong() bool {
 	return v.Flag.FlagProvided && !v.Flag.FlagProvidedAsAlias
 }
 
+// IsProvidedAbbreviated checks if current `string` flag was provided from stdin but using an abbreviation of its long name.
+func (v *ValueString) IsProvidedAbbreviated() bool {
+	return v.Flag.FlagProvided && v.Flag.FlagProvidedAsAbbrev
+}
+
+// Source returns where current `string` flag value comes from.
+func (v *ValueString) Source() Source {
+	return v.Flag.FlagSource
+}
+
 // GetFlagType returns the associated flag type.
 func (v *ValueString) GetFlagType() FlagString {
 	return v.Flag
@@ -149,7 +184,57 @@ func (v *ValueStringSlice) IsProvidedLong() bool {
 	return v.Flag.FlagProvided && !v.Flag.FlagProvidedAsAlias
 }
 
+// IsProvidedAbbreviated checks if current string slice flag was provided from stdin but using an abbreviation of its long name.
+func (v *ValueStringSlice) IsProvidedAbbreviated() bool {
+	return v.Flag.FlagProvided && v.Flag.FlagProvidedAsAbbrev
+}
+
+// Source returns where current string slice flag value comes from.
+func (v *ValueStringSlice) Source() Source {
+	return v.Flag.FlagSource
+}
+
 // GetFlagType returns the associated flag type.
 func (v *ValueStringSlice) GetFlagType() FlagStringSlice {
 	return v.Flag
 }
+
+// ValuePath represents a path type flag value.
+type ValuePath struct {
+	Flag FlagPath
+}
+
+// Value unwraps the plain path value of the current flag.
+func (v *ValuePath) Value() string {
+	return v.Flag.FlagValue.ToString()
+}
+
+// IsProvided checks if current path flag was provided from stdin.
+func (v *ValuePath) IsProvided() bool {
+	return v.Flag.FlagProvided
+}
+
+// IsProvidedShort checks if current path flag was provided from stdin but using its short name.
+func (v *ValuePath) IsProvidedShort() bool {
+	return v.Flag.FlagProvided && v.Flag.FlagProvidedAsAlias
+}
+
+// IsProvidedLong checks if current path flag was provided from stdin but using its long name.
+func (v *ValuePath) IsProvidedLong() bool {
+	return v.Flag.FlagProvided && !v.Flag.FlagProvidedAsAlias
+}
+
+// IsProvidedAbbreviated checks if current path flag was provided from stdin but using an abbreviation of its long name.
+func (v *ValuePath) IsProvidedAbbreviated() bool {
+	return v.Flag.FlagProvided && v.Flag.FlagProvidedAsAbbrev
+}
+
+// Source returns where current path flag value comes from.
+func (v *ValuePath) Source() Source {
+	return v.Flag.FlagSource
+}
+
+// GetFlagType returns the associated flag type.
+func (v *ValuePath) GetFlagType() FlagPath {
+	return v.Flag
+}
@@ -45,6 +45,11 @@ func (v *FlagValues) FindByKey(longFlagName string) (flag Flag) {
 				flag = f
 				return
 			}
+		case FlagPath:
+			if f.Name == longFlagName {
+				flag = f
+				return
+			}
 		}
 	}
 	return
@@ -106,6 +111,18 @@ func (v *FlagValues) GetProvidedFlags(providedOnly bool, providedAliasOnly bool)
 				flags = append(flags, f)
 				continue
 			}
+		case FlagPath:
+			if !f.FlagProvided {
+				continue
+			}
+			if providedOnly {
+				flags = append(flags, f)
+				continue
+			}
+			if providedAliasOnly && f.FlagProvidedAsAlias {
+				flags = append(flags, f)
+				continue
+			}
 		}
 	}
 	return
@@ -140,6 +157,8 @@ func (v *FlagValues) Value(longFlagName string) Value {
 		return f.FlagValue
 	case FlagStringSlice:
 		return f.FlagValue
+	case FlagPath:
+		return f.FlagValue
 	default:
 		return Value("")
 	}
@@ -216,3 +235,21 @@ func (v *FlagValues) StringSlice(longFlagName string) (val *ValueStringSlice, er
 		return
 	}
 }
+
+// Path finds a path flag value which value type should match
+// with its flag definition type, otherwise it returns an error.
+func (v *FlagValues) Path(longFlagName string) (val *ValuePath, err error) {
+	switch f := v.FindByKey(longFlagName).(type) {
+	case FlagPath:
+		val = &ValuePath{Flag: f}
+		return
+	default:
+		t := strings.ReplaceAll(fmt.Sprintf("%T", f), "cline.", "")
+		err = fmt.Errorf(
+			"error: flag `--%s` value used as `FlagPathValue` but declared as `%s`",
+			longFlagName,
+			t,
+		)
+		return
+	}
+}
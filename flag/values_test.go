@@ -330,6 +330,36 @@ func TestFlagBoolValue_IsProvidedLong(t *testing.T) {
 	}
 }
 
+func TestFlagBoolValue_IsProvidedAbbreviated(t *testing.T) {
+	tests := []struct {
+		name     string
+		flag     flag.FlagBool
+		expected bool
+	}{
+		{
+			name: "should return false when no provided abbreviated flag",
+			flag: flag.FlagBool{Name: "verbose", FlagProvided: true},
+		},
+		{
+			name: "should return true when provided abbreviated flag",
+			flag: flag.FlagBool{
+				Name:                 "verbose",
+				FlagValue:            flag.Value("true"),
+				FlagProvided:         true,
+				FlagProvidedAsAbbrev: true,
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := flag.ValueBool{Flag: tt.flag}
+			assert.Equal(t, tt.expected, v.IsProvidedAbbreviated(), "IsProvidedAbbreviated value does not match the expected one")
+		})
+	}
+}
+
 func TestFlagBoolValue_GetFlagType(t *testing.T) {
 	type fields struct {
 		flag flag.FlagBool
@@ -1738,3 +1768,20 @@ func TestFlagIntValue_GetFlagType(t *testing.T) {
 		})
 	}
 }
+
+func TestFlagValues_Path(t *testing.T) {
+	values := flag.NewFlagValues([]flag.Flag{
+		flag.FlagPath{Name: "out", FlagValue: "/tmp/out", FlagProvided: true, FlagProvidedAsAlias: true},
+		flag.FlagString{Name: "name"},
+	})
+
+	v, err := values.Path("out")
+	assert.NoError(t, err)
+	assert.Equal(t, "/tmp/out", v.Value())
+	assert.True(t, v.IsProvidedShort())
+	assert.Equal(t, flag.Value("/tmp/out"), values.Value("out"))
+	assert.Len(t, values.GetProvided(), 1)
+
+	_, err = values.Path("name")
+	assert.EqualError(t, err, "error: flag `--name` value used as `FlagPathValue` but declared as `flag.FlagString`")
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/config"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+// configPath returns the config file path provided via the config flag or the first existing search path.
+// The config flag value of a path flag is expanded and checked like the other path flag values.
+// The search paths default to the `config` files of the application config directory.
+func configPath(ap *app.App, lookup flag.LookupEnvFunc) (string, error) {
+	if ap.ConfigFlag != "" {
+		_, fl, _ := helpers.FindFlagByKey(ap.ConfigFlag, ap.Flags)
+		switch f := fl.(type) {
+		case flag.FlagString:
+			if path := f.FlagValue.ToString(); path != "" {
+				return path, nil
+			}
+		case flag.FlagPath:
+			if f.FlagValue.ToString() != "" {
+				flags := []flag.Flag{f}
+				if err := resolvePathFlags(flags, lookup); err != nil {
+					return "", err
+				}
+				return flags[0].(flag.FlagPath).FlagValue.ToString(), nil
+			}
+		default:
+			return "", &helpers.ParseError{
+				Kind:    helpers.KindInvalidDeclaration,
+				Index:   -1,
+				Flag:    ap.ConfigFlag,
+				Message: fmt.Sprintf("error: config flag '--%s' is not a declared string or path flag", ap.ConfigFlag),
+			}
+		}
+	}
+	paths := ap.ConfigPaths
+	if len(paths) == 0 {
+		paths = defaultConfigPaths(ap, lookup)
+	}
+	return config.Find(paths), nil
+}
+
+// defaultConfigPaths returns the `config` file paths of the application config directory
+// (e.g. `~/.config/<name>/config.json`) for the built-in and the custom decoders extensions.
+func defaultConfigPaths(ap *app.App, lookup flag.LookupEnvFunc) []string {
+	dir := ap.UserDir(app.DirConfig, lookup)
+	if dir == "" {
+		return nil
+	}
+	exts := []string{".json"}
+	for _, ext := range slices.Sorted(maps.Keys(ap.ConfigDecoders)) {
+		if ext != ".json" {
+			exts = append(exts, ext)
+		}
+	}
+	paths := make([]string, len(exts))
+	for i, ext := range exts {
+		paths[i] = filepath.Join(dir, "config"+ext)
+	}
+	return paths
+}
+
+// applyConfig sets the config file values of the application and command flags
+// which were not provided via the command line or environment variables.
+// The values of non-path flags are expanded via a given function.
+func applyConfig(ap *app.App, cmd *app.Cmd, lookup flag.LookupEnvFunc, expand func(string) string) error {
+	path, err := configPath(ap, lookup)
+	if err != nil || path == "" {
+		return err
+	}
+
+	values, err := config.Load(path, ap.ConfigDecoders)
+	if err != nil {
+		return &helpers.ParseError{
+			Kind:    helpers.KindInvalidArgument,
+			Index:   -1,
+			Token:   path,
+			Message: err.Error(),
+			Err:     err,
+		}
+	}
+
+	if err := applyConfigValues(ap.Flags, values, path, "", expand); err != nil {
+		return err
+	}
+	if cmd != nil {
+		return applyConfigValues(cmd.Flags, config.Section(values, cmd.Name), path, cmd.Name, expand)
+	}
+	return nil
+}
+
+// applyConfigValues sets the given config values of a list of flags in place.
+func applyConfigValues(flags []flag.Flag, values map[string]any, path string, cmdName string, expand func(string) string) error {
+	for i, fl := range flags {
+		name, _ := helpers.FlagNames(fl)
+		v, ok := config.Lookup(values, name)
+		if !ok || flagSource(fl).Kind != flag.SourceDefault {
+			continue
+		}
+
+		s, err := config.ToString(v)
+		if _, isPath := fl.(flag.FlagPath); err == nil && !isPath {
+			s = expand(s)
+		}
+		if err == nil {
+			src := flag.Source{Kind: flag.SourceConfig, File: path}
+			switch f := fl.(type) {
+			case flag.FlagBool:
+				if _, err = flag.Value(s).ToBool(); err == nil {
+					f.FlagValue = flag.Value(s)
+					f.FlagSource = src
+					flags[i] = f
+				}
+			case flag.FlagInt:
+				if _, err = flag.Value(s).ToInt(); err == nil {
+					f.FlagValue = flag.Value(s)
+					f.FlagSource = src
+					flags[i] = f
+				}
+			case flag.FlagString:
+				f.FlagValue = flag.Value(s)
+				f.FlagSource = src
+				flags[i] = f
+			case flag.FlagStringSlice:
+				f.FlagValue = flag.Value(s)
+				f.FlagSource = src
+				flags[i] = f
+			case flag.FlagPath:
+				f.FlagValue = flag.Value(s)
+				f.FlagSource = src
+				flags[i] = f
+			}
+		}
+		if err != nil {
+			return &helpers.ParseError{
+				Kind:    helpers.KindInvalidValue,
+				Index:   -1,
+				Flag:    name,
+				Command: cmdName,
+				Token:   path,
+				Message: fmt.Sprintf("error: invalid value for flag '--%s' in config file '%s'", name, path),
+				Err:     err,
+			}
+		}
+	}
+	return nil
+}
+
+// flagSource returns where a given flag value comes from.
+func flagSource(fl flag.Flag) flag.Source {
+	switch f := fl.(type) {
+	case flag.FlagBool:
+		return f.FlagSource
+	case flag.FlagInt:
+		return f.FlagSource
+	case flag.FlagString:
+		return f.FlagSource
+	case flag.FlagStringSlice:
+		return f.FlagSource
+	case flag.FlagPath:
+		return f.FlagSource
+	}
+	return flag.Source{}
+}
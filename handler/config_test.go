@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/config"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+func TestHandler_Config(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tool.json")
+	content := `{"color": "never", "port": 3000, "log_level": "debug", "tags": ["a", "b"], "info": {"trace": true}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	invalidPath := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalidPath, []byte(`{"port": "abc"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missingPath := filepath.Join(dir, "none.json")
+
+	newApp := func(configFlag string, configPaths []string) *app.App {
+		return &app.App{
+			Name:        "tool",
+			ConfigFlag:  configFlag,
+			ConfigPaths: configPaths,
+			Flags: []flag.Flag{
+				flag.FlagString{Name: "config", Aliases: []string{"c"}},
+				flag.FlagString{Name: "color", Value: "auto", EnvVar: "TOOL_COLOR"},
+				flag.FlagInt{Name: "port", Value: 80},
+				flag.FlagString{Name: "log-level", Value: "info"},
+				flag.FlagStringSlice{Name: "tags"},
+			},
+			Commands: []app.Cmd{
+				{
+					Name:  "info",
+					Flags: []flag.Flag{flag.FlagBool{Name: "trace"}, flag.FlagBool{Name: "all"}},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		configFlag  string
+		configPaths []string
+		env         map[string]string
+		vargs       []string
+		wantErr     string
+		wantErrIs   []error
+		wantHelp    bool
+		wantColor   string
+		wantPort    int
+		wantLevel   string
+		wantTags    []string
+		wantTrace   bool
+	}{
+		{
+			name:       "should apply config values via the config flag",
+			configFlag: "config",
+			vargs:      []string{"tool", "-c", path, "info"},
+			wantColor:  "never",
+			wantPort:   3000,
+			wantLevel:  "debug",
+			wantTags:   []string{"a", "b"},
+			wantTrace:  true,
+		},
+		{
+			name:       "should prefer command line and environment values",
+			configFlag: "config",
+			env:        map[string]string{"TOOL_COLOR": "always"},
+			vargs:      []string{"tool", "--config", path, "--port", "9000"},
+			wantColor:  "always",
+			wantPort:   9000,
+			wantLevel:  "debug",
+			wantTags:   []string{"a", "b"},
+		},
+		{
+			name:        "should search config paths when no config path is provided",
+			configPaths: []string{missingPath, path},
+			vargs:       []string{"tool"},
+			wantColor:   "never",
+			wantPort:    3000,
+			wantLevel:   "debug",
+			wantTags:    []string{"a", "b"},
+		},
+		{
+			name:       "should keep defaults without config files",
+			configFlag: "config",
+			vargs:      []string{"tool"},
+			wantColor:  "auto",
+			wantPort:   80,
+			wantLevel:  "info",
+		},
+		{
+			name:       "should not load config files for help",
+			configFlag: "config",
+			vargs:      []string{"tool", "-c", missingPath, "--help"},
+			wantHelp:   true,
+			wantColor:  "auto",
+			wantPort:   80,
+			wantLevel:  "info",
+		},
+		{
+			name:       "should report missing config files",
+			configFlag: "config",
+			vargs:      []string{"tool", "-c", missingPath},
+			wantErrIs:  []error{os.ErrNotExist, helpers.ErrInvalidArgument},
+		},
+		{
+			name:       "should report invalid config values",
+			configFlag: "config",
+			vargs:      []string{"tool", "-c", invalidPath},
+			wantErr:    "error: invalid value for flag '--port' in config file '" + invalidPath + "'",
+			wantErrIs:  []error{helpers.ErrInvalidValue},
+		},
+		{
+			name:       "should report undeclared config flags",
+			configFlag: "file",
+			vargs:      []string{"tool"},
+			wantErr:    "error: config flag '--file' is not a declared string or path flag",
+			wantErrIs:  []error{helpers.ErrInvalidDeclaration},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ap := newApp(tt.configFlag, tt.configPaths)
+			result, err := NewWithOpts(ap, Options{LookupEnv: flag.LookupEnvMap(tt.env)}).Parse(tt.vargs)
+			assert.Equal(t, newApp(tt.configFlag, tt.configPaths), ap)
+			if len(tt.wantErrIs) > 0 {
+				if tt.wantErr != "" {
+					assert.EqualError(t, err, tt.wantErr)
+				}
+				for _, target := range tt.wantErrIs {
+					assert.ErrorIs(t, err, target)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantHelp, result.Help)
+
+			color, _ := result.Flags.String("color")
+			assert.Equal(t, tt.wantColor, color.Value())
+			port, _ := result.Flags.Int("port")
+			v, _ := port.Value()
+			assert.Equal(t, tt.wantPort, v)
+			level, _ := result.Flags.String("log-level")
+			assert.Equal(t, tt.wantLevel, level.Value())
+			if tt.wantTags != nil {
+				tags, _ := result.Flags.StringSlice("tags")
+				assert.Equal(t, tt.wantTags, tags.Value())
+			}
+			if result.CmdFlags != nil {
+				trace, _ := result.CmdFlags.Bool("trace")
+				b, _ := trace.Value()
+				assert.Equal(t, tt.wantTrace, b)
+				assert.False(t, trace.IsProvided())
+			}
+		})
+	}
+}
+
+func TestHandler_ConfigPathFlag(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tool.json"), []byte(`{"color": "never"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		vargs     []string
+		wantErr   string
+		wantColor string
+	}{
+		{
+			name:      "should expand the config path flag value",
+			vargs:     []string{"tool", "--config", "$CONFIG_DIR/tool.json"},
+			wantColor: "never",
+		},
+		{
+			name:      "should expand the home directory of the config path flag value",
+			vargs:     []string{"tool", "--config", "~/tool.json"},
+			wantColor: "never",
+		},
+		{
+			name:      "should not require the config path flag",
+			vargs:     []string{"tool"},
+			wantColor: "auto",
+		},
+		{
+			name:    "should check the config path flag value",
+			vargs:   []string{"tool", "--config", "$CONFIG_DIR/missing.json"},
+			wantErr: "error: path '" + filepath.Join(dir, "missing.json") + "' of flag '--config' does not exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ap := &app.App{
+				Name:       "tool",
+				ConfigFlag: "config",
+				Flags: []flag.Flag{
+					flag.FlagPath{Name: "config", MustExist: true},
+					flag.FlagString{Name: "color", Value: "auto"},
+				},
+			}
+			lookup := flag.LookupEnvMap(map[string]string{"CONFIG_DIR": dir, "HOME": dir})
+			result, err := NewWithOpts(ap, Options{LookupEnv: lookup}).Parse(tt.vargs)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			color, _ := result.Flags.String("color")
+			assert.Equal(t, tt.wantColor, color.Value())
+		})
+	}
+}
+
+func TestHandler_ConfigDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "config")
+	if err := os.MkdirAll(filepath.Join(dir, "tool"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "tool", "config.json")
+	if err := os.WriteFile(path, []byte(`{"color": "never"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		configPaths []string
+		wantColor   string
+		wantSource  flag.Source
+	}{
+		{
+			name:       "should search the config file in the application config directory",
+			wantColor:  "never",
+			wantSource: flag.Source{Kind: flag.SourceConfig, File: path},
+		},
+		{
+			name:        "should prefer the declared search paths",
+			configPaths: []string{filepath.Join(dir, "missing.json")},
+			wantColor:   "auto",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ap := &app.App{
+				Name:        "tool",
+				ConfigFlag:  "config",
+				ConfigPaths: tt.configPaths,
+				Flags: []flag.Flag{
+					flag.FlagString{Name: "config"},
+					flag.FlagString{Name: "color", Value: "auto"},
+				},
+			}
+			lookup := flag.LookupEnvMap(map[string]string{"XDG_CONFIG_HOME": dir})
+			result, err := NewWithOpts(ap, Options{LookupEnv: lookup}).Parse([]string{"tool"})
+			assert.NoError(t, err)
+
+			color, _ := result.Flags.String("color")
+			assert.Equal(t, tt.wantColor, color.Value())
+			assert.Equal(t, tt.wantSource, color.Source())
+		})
+	}
+}
+
+func TestHandler_ContextDirs(t *testing.T) {
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	if err := os.WriteFile(envFile, []byte("XDG_CONFIG_HOME="+filepath.Join(dir, "dotenv")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		envFiles []string
+		lookup   map[string]string
+		want     string
+	}{
+		{
+			name:   "should resolve the directories via the handler lookup",
+			lookup: map[string]string{"XDG_CONFIG_HOME": filepath.Join(dir, "opts")},
+			want:   filepath.Join(dir, "opts", "tool"),
+		},
+		{
+			name:     "should resolve the directories via the dotenv files",
+			envFiles: []string{envFile},
+			lookup:   map[string]string{},
+			want:     filepath.Join(dir, "dotenv", "tool"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			ap := &app.App{
+				Name:     "tool",
+				EnvFiles: tt.envFiles,
+				Handler: func(ctx *app.AppContext) error {
+					got = ctx.ConfigDir()
+					return nil
+				},
+			}
+			err := NewWithOpts(ap, Options{LookupEnv: flag.LookupEnvMap(tt.lookup)}).Run([]string{"tool"})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_defaultConfigPaths(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "config")
+	lookup := flag.LookupEnvMap(map[string]string{"XDG_CONFIG_HOME": dir})
+	ap := &app.App{Name: "tool", ConfigDecoders: map[string]config.Decoder{".yaml": nil, ".json": nil, ".toml": nil}}
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "tool", "config.json"),
+		filepath.Join(dir, "tool", "config.toml"),
+		filepath.Join(dir, "tool", "config.yaml"),
+	}, defaultConfigPaths(ap, lookup))
+	assert.Nil(t, defaultConfigPaths(&app.App{}, lookup))
+}
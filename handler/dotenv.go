@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"os"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/dotenv"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+// loadEnvFiles loads the application dotenv files (if any) on top of a given environment lookup function
+// and returns their variables together with the function to look up the flag environment variables.
+func loadEnvFiles(ap *app.App, lookup flag.LookupEnvFunc) (map[string]string, flag.LookupEnvFunc, error) {
+	if len(ap.EnvFiles) == 0 {
+		return nil, lookup, nil
+	}
+	vars, err := dotenv.Load(ap.EnvFiles, ap.EnvOverride, lookup)
+	if err != nil {
+		return nil, nil, &helpers.ParseError{
+			Kind:    helpers.KindInvalidArgument,
+			Index:   -1,
+			Message: err.Error(),
+			Err:     err,
+		}
+	}
+	return vars, dotenv.LookupFunc(vars, ap.EnvOverride, lookup), nil
+}
+
+// exportEnv sets the given variables in the process environment.
+// Existing variables are only replaced when override is true.
+func exportEnv(vars map[string]string, override bool) error {
+	for k, v := range vars {
+		if _, exists := os.LookupEnv(k); exists && !override {
+			continue
+		}
+		if err := os.Setenv(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+func TestHandler_EnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	content := "export TOOL_HOST=example.com\nTOOL_PORT=${TOOL_PORT_BASE:-80}80\nTOOL_OPTS='--verbose'\nCLINE_DOTENV_TEST=dotenv\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	invalidPath := filepath.Join(dir, ".env.invalid")
+	if err := os.WriteFile(invalidPath, []byte("INVALID"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		envFiles     []string
+		override     bool
+		setenv       bool
+		wantErr      string
+		wantHost     string
+		wantPort     int
+		wantExported string
+	}{
+		{
+			name:     "should resolve flag environment variables via the dotenv files",
+			wantHost: "example.com",
+			wantPort: 8080,
+		},
+		{
+			name:     "should expand the dotenv references via the environment",
+			env:      map[string]string{"TOOL_PORT_BASE": "90"},
+			wantHost: "example.com",
+			wantPort: 9080,
+		},
+		{
+			name:     "should prefer the environment unless overriding",
+			env:      map[string]string{"TOOL_HOST": "localhost"},
+			wantHost: "localhost",
+			wantPort: 8080,
+		},
+		{
+			name:     "should prefer the dotenv files when overriding",
+			env:      map[string]string{"TOOL_HOST": "localhost"},
+			override: true,
+			wantHost: "example.com",
+			wantPort: 8080,
+		},
+		{
+			name:         "should export the variables to the process environment when enabled",
+			setenv:       true,
+			wantHost:     "example.com",
+			wantPort:     8080,
+			wantExported: "dotenv",
+		},
+		{
+			name:     "should report dotenv errors",
+			envFiles: []string{invalidPath},
+			wantErr:  "error: " + invalidPath + ":1: missing '=' separator",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setenv {
+				t.Cleanup(func() {
+					for _, name := range []string{"TOOL_HOST", "TOOL_PORT", "TOOL_OPTS", "CLINE_DOTENV_TEST"} {
+						os.Unsetenv(name)
+					}
+				})
+			}
+
+			envFiles := tt.envFiles
+			if envFiles == nil {
+				envFiles = []string{filepath.Join(dir, ".env.missing"), path}
+			}
+			var exported string
+			ap := &app.App{
+				Name:        "tool",
+				EnvFiles:    envFiles,
+				EnvOverride: tt.override,
+				EnvSetenv:   tt.setenv,
+				OptsEnvVar:  "TOOL_OPTS",
+				Flags: []flag.Flag{
+					flag.FlagString{Name: "host", EnvVar: "TOOL_HOST"},
+					flag.FlagInt{Name: "port", EnvVar: "TOOL_PORT"},
+					flag.FlagBool{Name: "verbose"},
+				},
+				Handler: func(ctx *app.AppContext) error {
+					exported = os.Getenv("CLINE_DOTENV_TEST")
+					return nil
+				},
+			}
+			h := NewWithOpts(ap, Options{LookupEnv: flag.LookupEnvMap(tt.env)})
+			result, err := h.Parse([]string{"tool"})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, helpers.ErrInvalidArgument)
+				return
+			}
+			assert.NoError(t, err)
+
+			host, _ := result.Flags.String("host")
+			assert.Equal(t, tt.wantHost, host.Value())
+			port, _ := result.Flags.Int("port")
+			v, _ := port.Value()
+			assert.Equal(t, tt.wantPort, v)
+			verbose, _ := result.Flags.Bool("verbose")
+			assert.True(t, verbose.IsProvided())
+			assert.Equal(t, "example.com", result.Env["TOOL_HOST"])
+
+			assert.NoError(t, h.Execute(result))
+			assert.Equal(t, tt.wantExported, exported)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+// lookupEnvArgs returns the default arguments of a given environment variable split using shell quoting rules.
+// The environment variable is looked up via a given function.
+func lookupEnvArgs(lookup flag.LookupEnvFunc, name string) ([]string, error) {
+	if name == "" {
+		return nil, nil
+	}
+	value, ok := lookup(name)
+	if !ok {
+		return nil, nil
+	}
+	args, err := helpers.SplitArgs(value)
+	if err != nil {
+		return nil, &helpers.ParseError{
+			Kind:    helpers.KindInvalidArgument,
+			Index:   -1,
+			Token:   value,
+			Message: fmt.Sprintf("error: invalid arguments in environment variable '%s': %s", name, err),
+			Err:     err,
+		}
+	}
+	return args, nil
+}
+
+// applyEnvArgs sets the values of a list of flags from the default arguments of a given environment variable (if any)
+// and returns the number of arguments found. The default arguments only accept the flags of the given map and their values,
+// so they never select commands or tail arguments. The flags get the environment variable as their source
+// and they are not marked as assigned, so the command line flags override them.
+func (h *Handler) applyEnvArgs(
+	flags []flag.Flag,
+	flagMap map[string]helpers.FlagInfo,
+	envVar string,
+	lookup flag.LookupEnvFunc,
+	stdin io.Reader,
+	expand func(string) string,
+) (int, error) {
+	args, err := lookupEnvArgs(lookup, envVar)
+	if err != nil || len(args) == 0 {
+		return 0, err
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) > h.opts.MaxArgLen {
+			return 0, envArgsError(
+				helpers.KindLimitExceeded, envVar, arg,
+				"argument exceeds maximum length of %d characters", h.opts.MaxArgLen,
+			)
+		}
+
+		key, isLong := strings.CutPrefix(arg, "--")
+		if !isLong {
+			key, _ = strings.CutPrefix(arg, "-")
+		}
+		if key == arg || key == "" {
+			return 0, envArgsError(helpers.KindInvalidArgument, envVar, arg, "unexpected argument '%s'", arg)
+		}
+		info, ok := flagMap[key]
+		if !ok {
+			err := envArgsError(helpers.KindUnknownFlag, envVar, arg, "unknown flag '%s' argument", arg)
+			err.Flag = key
+			return 0, err
+		}
+		src := flag.Source{Kind: flag.SourceEnv, EnvVar: envVar}
+
+		// A boolean flag is considered true on its own unless followed by a boolean value
+		if fb, ok := info.Flag.(flag.FlagBool); ok {
+			fb.FlagValue = flag.Value("true")
+			if i+1 < len(args) {
+				if _, err := flag.Value(args[i+1]).ToBool(); err == nil {
+					fb.FlagValue = flag.Value(args[i+1])
+					i++
+				}
+			}
+			fb.FlagProvided = true
+			fb.FlagProvidedAsAlias = !isLong
+			fb.FlagSource = src
+			flags[info.Index] = fb
+			continue
+		}
+
+		// Other flags require a value which is a single dash for the standard input of secret flags
+		name, _ := helpers.FlagNames(info.Flag)
+		fs, isSecret := info.Flag.(flag.FlagString)
+		isSecret = isSecret && fs.Secret
+		if i+1 == len(args) || (strings.HasPrefix(args[i+1], "-") && !(isSecret && args[i+1] == "-")) {
+			err := envArgsError(helpers.KindMissingValue, envVar, arg, "flag '--%s' requires a value", name)
+			err.Flag = name
+			return 0, err
+		}
+		i++
+		value := args[i]
+
+		switch f := info.Flag.(type) {
+		case flag.FlagInt:
+			s := flag.Value(expand(value))
+			if _, err := s.ToInt(); err != nil {
+				perr := envArgsError(helpers.KindInvalidValue, envVar, value, "invalid integer value for flag '--%s'", f.Name)
+				perr.Flag = f.Name
+				perr.Err = err
+				return 0, perr
+			}
+			f.FlagValue = s
+			f.FlagProvided = true
+			f.FlagProvidedAsAlias = !isLong
+			f.FlagSource = src
+			flags[info.Index] = f
+		case flag.FlagString:
+			f.FlagValue = flag.Value(expand(value))
+			if path := f.FlagValue.ToString(); f.Secret && (info.File || path == "-") {
+				secret, err := readSecret(stdin, path)
+				if err != nil {
+					perr := envArgsError(helpers.KindInvalidValue, envVar, value, "unable to read secret value of flag '--%s': %s", f.Name, err)
+					perr.Flag = f.Name
+					perr.Err = err
+					return 0, perr
+				}
+				f.FlagValue = flag.Value(secret)
+				if path != "-" {
+					src.File = path
+				}
+			}
+			f.FlagProvided = true
+			f.FlagProvidedAsAlias = !isLong
+			f.FlagSource = src
+			flags[info.Index] = f
+		case flag.FlagStringSlice:
+			f.FlagValue = flag.Value(expand(value))
+			f.FlagProvided = true
+			f.FlagProvidedAsAlias = !isLong
+			f.FlagSource = src
+			flags[info.Index] = f
+		case flag.FlagPath:
+			f.FlagValue = flag.Value(value)
+			f.FlagProvided = true
+			f.FlagProvidedAsAlias = !isLong
+			f.FlagSource = src
+			flags[info.Index] = f
+		}
+	}
+	return len(args), nil
+}
+
+// envArgsError creates a new parse error for the default arguments of a given environment variable.
+func envArgsError(kind helpers.ErrorKind, envVar string, token string, format string, a ...any) *helpers.ParseError {
+	return &helpers.ParseError{
+		Kind:    kind,
+		Index:   -1,
+		Token:   token,
+		Message: fmt.Sprintf("error: invalid arguments in environment variable '%s': ", envVar) + fmt.Sprintf(format, a...),
+	}
+}
+
+// cmdOptsEnvVar returns the default arguments environment variable of a given command
+// (e.g. `TOOL_INFO_OPTS` for `TOOL_OPTS`) or an empty string if the application one does not end with `_OPTS`.
+func cmdOptsEnvVar(name string, cmd string) string {
+	prefix, ok := strings.CutSuffix(name, "_OPTS")
+	if !ok {
+		return ""
+	}
+	return prefix + "_" + strings.ToUpper(strings.ReplaceAll(cmd, "-", "_")) + "_OPTS"
+}
+
+// applyEnvPrefix derives the environment variable names of the application and command flags without one
+// from the application and command prefixes. The commands are copied so the original ones are not modified.
+func applyEnvPrefix(ap *app.App) {
+	ap.Flags = helpers.WithEnvPrefix(ap.Flags, ap.EnvPrefix)
+	hasPrefix := ap.EnvPrefix != ""
+	for _, c := range ap.Commands {
+		if c.EnvPrefix != "" {
+			hasPrefix = true
+			break
+		}
+	}
+	if !hasPrefix {
+		return
+	}
+	cmds := make([]app.Cmd, len(ap.Commands))
+	for i, c := range ap.Commands {
+		prefix := c.EnvPrefix
+		if prefix == "" && ap.EnvPrefix != "" {
+			prefix = helpers.EnvVarName(ap.EnvPrefix, c.Name)
+		}
+		c.Flags = helpers.WithEnvPrefix(c.Flags, prefix)
+		cmds[i] = c
+	}
+	ap.Commands = cmds
+}
@@ -0,0 +1,430 @@
+package handler
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+func TestHandler_OptsEnvVar(t *testing.T) {
+	optsSource := flag.Source{Kind: flag.SourceEnv, EnvVar: "TOOL_OPTS"}
+
+	tests := []struct {
+		name            string
+		env             map[string]string
+		vargs           []string
+		opts            Options
+		defaultCommand  string
+		wantErr         string
+		wantErrIs       error
+		wantErrIndex    int
+		wantErrCommand  string
+		wantCmd         string
+		wantColor       string
+		wantColorSource flag.Source
+		wantVerbose     bool
+		wantTags        []string
+		wantForce       bool
+		wantTailArgs    []string
+	}{
+		{
+			name:            "should apply the application default arguments",
+			env:             map[string]string{"TOOL_OPTS": "--color never --verbose"},
+			vargs:           []string{"tool", "file.txt"},
+			wantColor:       "never",
+			wantColorSource: optsSource,
+			wantVerbose:     true,
+			wantTailArgs:    []string{"file.txt"},
+		},
+		{
+			name:            "should let command line flags override the default arguments",
+			env:             map[string]string{"TOOL_OPTS": `--color "always never" -v`},
+			vargs:           []string{"tool", "--color", "never"},
+			wantColor:       "never",
+			wantColorSource: flag.Source{Kind: flag.SourceArg, Index: 1},
+			wantVerbose:     true,
+		},
+		{
+			name:         "should keep the command line argument indexes in errors",
+			env:          map[string]string{"TOOL_OPTS": "--color never --verbose"},
+			vargs:        []string{"tool", "--colr"},
+			wantErr:      "error: unknown flag '--colr' argument (did you mean '--color'?)",
+			wantErrIs:    helpers.ErrUnknownFlag,
+			wantErrIndex: 1,
+		},
+		{
+			name: "should apply the command default arguments",
+			env: map[string]string{
+				"TOOL_OPTS":           "--verbose",
+				"TOOL_BUILD_ALL_OPTS": "--tags 'a, b'",
+			},
+			vargs:        []string{"tool", "ba", "x"},
+			wantCmd:      "build-all",
+			wantColor:    "auto",
+			wantVerbose:  true,
+			wantTags:     []string{"a", "b"},
+			wantTailArgs: []string{"x"},
+		},
+		{
+			name:      "should let command line flags override the command default arguments",
+			env:       map[string]string{"TOOL_BUILD_ALL_OPTS": "--tags 'a, b'"},
+			vargs:     []string{"tool", "build-all", "--tags", "c"},
+			wantCmd:   "build-all",
+			wantColor: "auto",
+			wantTags:  []string{"c"},
+		},
+		{
+			name:           "should apply the default command default arguments when no command is provided",
+			env:            map[string]string{"TOOL_BUILD_ALL_OPTS": "--tags a --force"},
+			vargs:          []string{"tool"},
+			defaultCommand: "build-all",
+			wantCmd:        "build-all",
+			wantColor:      "auto",
+			wantTags:       []string{"a"},
+			wantForce:      true,
+		},
+		{
+			name:           "should apply the default command default arguments when routing positional arguments",
+			env:            map[string]string{"TOOL_BUILD_ALL_OPTS": "--tags a --force"},
+			vargs:          []string{"tool", "x"},
+			defaultCommand: "build-all",
+			wantCmd:        "build-all",
+			wantColor:      "auto",
+			wantTags:       []string{"a"},
+			wantForce:      true,
+			wantTailArgs:   []string{"x"},
+		},
+		{
+			name:           "should apply the default command default arguments when selected via its flags",
+			env:            map[string]string{"TOOL_BUILD_ALL_OPTS": "--tags a --force"},
+			vargs:          []string{"tool", "--tags", "c"},
+			defaultCommand: "build-all",
+			wantCmd:        "build-all",
+			wantColor:      "auto",
+			wantTags:       []string{"c"},
+			wantForce:      true,
+		},
+		{
+			name:           "should report unknown flags of the default command default arguments",
+			env:            map[string]string{"TOOL_BUILD_ALL_OPTS": "--color never"},
+			vargs:          []string{"tool"},
+			defaultCommand: "build-all",
+			wantErr:        "error: invalid arguments in environment variable 'TOOL_BUILD_ALL_OPTS': unknown flag '--color' argument",
+			wantErrIs:      helpers.ErrUnknownFlag,
+			wantErrIndex:   -1,
+			wantErrCommand: "build-all",
+		},
+		{
+			name:         "should not accept positional default arguments",
+			env:          map[string]string{"TOOL_OPTS": "--verbose file.txt"},
+			vargs:        []string{"tool", "--color", "never"},
+			wantErr:      "error: invalid arguments in environment variable 'TOOL_OPTS': unexpected argument 'file.txt'",
+			wantErrIs:    helpers.ErrInvalidArgument,
+			wantErrIndex: -1,
+		},
+		{
+			name:           "should report unknown flags of the command default arguments",
+			env:            map[string]string{"TOOL_BUILD_ALL_OPTS": "--color never"},
+			vargs:          []string{"tool", "build-all"},
+			wantErr:        "error: invalid arguments in environment variable 'TOOL_BUILD_ALL_OPTS': unknown flag '--color' argument",
+			wantErrIs:      helpers.ErrUnknownFlag,
+			wantErrIndex:   -1,
+			wantErrCommand: "build-all",
+		},
+		{
+			name:         "should report default arguments without value",
+			env:          map[string]string{"TOOL_OPTS": "--color --verbose"},
+			vargs:        []string{"tool"},
+			wantErr:      "error: invalid arguments in environment variable 'TOOL_OPTS': flag '--color' requires a value",
+			wantErrIs:    helpers.ErrMissingValue,
+			wantErrIndex: -1,
+		},
+		{
+			name:         "should report invalid default arguments",
+			env:          map[string]string{"TOOL_OPTS": `--color "never`},
+			vargs:        []string{"tool"},
+			wantErr:      "error: invalid arguments in environment variable 'TOOL_OPTS': unterminated double quote",
+			wantErrIs:    helpers.ErrInvalidArgument,
+			wantErrIndex: -1,
+		},
+		{
+			name:         "should apply the arguments limits to the default arguments",
+			env:          map[string]string{"TOOL_OPTS": "--color never --verbose"},
+			vargs:        []string{"tool"},
+			opts:         Options{MaxArgsCount: 3},
+			wantErr:      "error: number of arguments exceeds the limit of 3",
+			wantErrIs:    helpers.ErrLimitExceeded,
+			wantErrIndex: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ap := &app.App{
+				Name:           "tool",
+				OptsEnvVar:     "TOOL_OPTS",
+				DefaultCommand: tt.defaultCommand,
+				Flags: []flag.Flag{
+					flag.FlagString{Name: "color", Value: "auto"},
+					flag.FlagBool{Name: "verbose", Aliases: []string{"v"}},
+				},
+				Commands: []app.Cmd{
+					{
+						Name:    "build-all",
+						Aliases: []string{"ba"},
+						Flags:   []flag.Flag{flag.FlagStringSlice{Name: "tags"}, flag.FlagBool{Name: "force"}},
+					},
+				},
+			}
+			opts := tt.opts
+			opts.LookupEnv = flag.LookupEnvMap(tt.env)
+
+			result, err := NewWithOpts(ap, opts).Parse(tt.vargs)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				var perr *helpers.ParseError
+				if assert.ErrorAs(t, err, &perr) {
+					assert.Equal(t, tt.wantErrIndex, perr.Index)
+					assert.Equal(t, tt.wantErrCommand, perr.Command)
+				}
+				return
+			}
+			assert.NoError(t, err)
+
+			color, _ := result.Flags.String("color")
+			assert.Equal(t, tt.wantColor, color.Value())
+			assert.Equal(t, tt.wantColorSource, color.Source())
+			verbose, _ := result.Flags.Bool("verbose")
+			assert.Equal(t, tt.wantVerbose, verbose.IsProvided())
+			assert.ElementsMatch(t, tt.wantTailArgs, result.TailArgs)
+			if tt.wantCmd != "" {
+				assert.Equal(t, tt.wantCmd, result.Cmd.Name)
+				tags, _ := result.CmdFlags.StringSlice("tags")
+				assert.Equal(t, tt.wantTags, tags.Value())
+				force, _ := result.CmdFlags.Bool("force")
+				assert.Equal(t, tt.wantForce, force.IsProvided())
+			}
+		})
+	}
+}
+
+func Test_cmdOptsEnvVar(t *testing.T) {
+	tests := []struct {
+		name   string
+		envVar string
+		cmd    string
+		want   string
+	}{
+		{
+			name:   "should derive the command variable",
+			envVar: "TOOL_OPTS",
+			cmd:    "info",
+			want:   "TOOL_INFO_OPTS",
+		},
+		{
+			name:   "should replace dashes of the command name",
+			envVar: "TOOL_OPTS",
+			cmd:    "build-all",
+			want:   "TOOL_BUILD_ALL_OPTS",
+		},
+		{
+			name:   "should not derive a variable without the opts suffix",
+			envVar: "TOOLFLAGS",
+			cmd:    "info",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cmdOptsEnvVar(tt.envVar, tt.cmd))
+		})
+	}
+}
+
+func TestHandler_EnvPrefix(t *testing.T) {
+	newApp := func() *app.App {
+		return &app.App{
+			Name:      "tool",
+			EnvPrefix: "TOOL",
+			Flags: []flag.Flag{
+				flag.FlagBool{Name: "trace"},
+				flag.FlagString{Name: "color", EnvVar: "COLOR"},
+			},
+			Commands: []app.Cmd{
+				{Name: "info", Flags: []flag.Flag{flag.FlagBool{Name: "trace"}}},
+				{Name: "build", EnvPrefix: "BUILDER", Flags: []flag.Flag{flag.FlagInt{Name: "jobs"}}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		env            map[string]string
+		vargs          []string
+		wantSources    map[string]flag.Source
+		wantValues     map[string]flag.Value
+		wantCmdSources map[string]flag.Source
+		wantCmdValues  map[string]flag.Value
+	}{
+		{
+			name:           "should derive the application and command flags environment variables",
+			env:            map[string]string{"TOOL_TRACE": "true", "TOOL_INFO_TRACE": "true", "TOOL_COLOR": "never"},
+			vargs:          []string{"tool", "info"},
+			wantSources:    map[string]flag.Source{"trace": {Kind: flag.SourceEnv, EnvVar: "TOOL_TRACE"}, "color": {}},
+			wantValues:     map[string]flag.Value{"trace": "true", "color": ""},
+			wantCmdSources: map[string]flag.Source{"trace": {Kind: flag.SourceEnv, EnvVar: "TOOL_INFO_TRACE"}},
+			wantCmdValues:  map[string]flag.Value{"trace": "true"},
+		},
+		{
+			name:           "should use the command prefix",
+			env:            map[string]string{"BUILDER_JOBS": "4", "TOOL_BUILD_JOBS": "2"},
+			vargs:          []string{"tool", "build"},
+			wantCmdSources: map[string]flag.Source{"jobs": {Kind: flag.SourceEnv, EnvVar: "BUILDER_JOBS"}},
+			wantCmdValues:  map[string]flag.Value{"jobs": "4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ap := newApp()
+			result, err := NewWithOpts(ap, Options{LookupEnv: flag.LookupEnvMap(tt.env)}).Parse(tt.vargs)
+			assert.NoError(t, err)
+			assert.Equal(t, newApp(), ap)
+
+			for name, want := range tt.wantSources {
+				assert.Equal(t, want, flagSource(result.Flags.FindByKey(name)), name)
+				assert.Equal(t, tt.wantValues[name], result.Flags.Value(name), name)
+			}
+			for name, want := range tt.wantCmdSources {
+				assert.Equal(t, want, flagSource(result.CmdFlags.FindByKey(name)), name)
+				assert.Equal(t, tt.wantCmdValues[name], result.CmdFlags.Value(name), name)
+			}
+		})
+	}
+}
+
+func TestHandler_EnvMode(t *testing.T) {
+	const (
+		workersWarning = "warning: invalid value 'ten' of environment variable 'WORKERS' for flag '--workers', using the default value\n"
+		dryWarning     = "warning: invalid value 'maybe' of environment variable 'DRY' for flag '--dry', using the default value\n"
+	)
+	invalidEnv := map[string]string{"WORKERS": "ten", "DRY": "maybe"}
+
+	tests := []struct {
+		name        string
+		vargs       []string
+		env         map[string]string
+		mode        helpers.EnvMode
+		logPrefix   string
+		wantErr     string
+		wantCommand string
+		wantErrOut  string
+		wantLog     string
+		wantWorkers int
+	}{
+		{
+			name:    "should report invalid application flag values in strict mode",
+			vargs:   []string{"tool"},
+			env:     invalidEnv,
+			mode:    helpers.EnvModeStrict,
+			wantErr: "error: invalid value 'ten' of environment variable 'WORKERS' for flag '--workers'",
+		},
+		{
+			name:        "should report invalid selected command flag values in strict mode",
+			vargs:       []string{"tool", "run"},
+			env:         map[string]string{"DRY": "maybe"},
+			mode:        helpers.EnvModeStrict,
+			wantErr:     "error: invalid value 'maybe' of environment variable 'DRY' for flag '--dry'",
+			wantCommand: "run",
+		},
+		{
+			name:        "should not check the flags of other commands in strict mode",
+			vargs:       []string{"tool"},
+			env:         map[string]string{"DRY": "maybe"},
+			mode:        helpers.EnvModeStrict,
+			wantWorkers: 4,
+		},
+		{
+			name:        "should not check the flags provided via the command line in strict mode",
+			vargs:       []string{"tool", "--workers", "2"},
+			env:         map[string]string{"WORKERS": "ten"},
+			mode:        helpers.EnvModeStrict,
+			wantWorkers: 2,
+		},
+		{
+			name:        "should not check the flags for help in strict mode",
+			vargs:       []string{"tool", "--help"},
+			env:         invalidEnv,
+			mode:        helpers.EnvModeStrict,
+			wantWorkers: 4,
+		},
+		{
+			name:        "should warn about invalid values via the application error writer",
+			vargs:       []string{"tool", "run"},
+			env:         invalidEnv,
+			mode:        helpers.EnvModeWarn,
+			wantErrOut:  workersWarning + dryWarning,
+			wantWorkers: 4,
+		},
+		{
+			name:        "should only warn about the application and selected command flags",
+			vargs:       []string{"tool"},
+			env:         invalidEnv,
+			mode:        helpers.EnvModeWarn,
+			wantErrOut:  workersWarning,
+			wantWorkers: 4,
+		},
+		{
+			name:        "should warn about invalid values via a custom logger",
+			vargs:       []string{"tool"},
+			env:         invalidEnv,
+			mode:        helpers.EnvModeWarn,
+			logPrefix:   "tool: ",
+			wantLog:     "tool: " + workersWarning,
+			wantWorkers: 4,
+		},
+		{
+			name:        "should ignore invalid values by default",
+			vargs:       []string{"tool", "run"},
+			env:         invalidEnv,
+			wantWorkers: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errOut, logOut bytes.Buffer
+			ap := &app.App{
+				Name:      "tool",
+				ErrWriter: &errOut,
+				Flags:     []flag.Flag{flag.FlagInt{Name: "workers", Value: 4, EnvVar: "WORKERS"}},
+				Commands:  []app.Cmd{{Name: "run", Flags: []flag.Flag{flag.FlagBool{Name: "dry", EnvVar: "DRY"}}}},
+			}
+			opts := Options{LookupEnv: flag.LookupEnvMap(tt.env), EnvMode: tt.mode}
+			if tt.logPrefix != "" {
+				opts.EnvLogger = log.New(&logOut, tt.logPrefix, 0)
+			}
+
+			result, err := NewWithOpts(ap, opts).Parse(tt.vargs)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, helpers.ErrInvalidValue)
+				var perr *helpers.ParseError
+				if assert.ErrorAs(t, err, &perr) {
+					assert.Equal(t, tt.wantCommand, perr.Command)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantErrOut, errOut.String())
+			assert.Equal(t, tt.wantLog, logOut.String())
+
+			workers, _ := result.Flags.Int("workers")
+			v, _ := workers.Value()
+			assert.Equal(t, tt.wantWorkers, v)
+		})
+	}
+}
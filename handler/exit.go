@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joseluisq/cline/helpers"
+)
+
+// Exit status codes used by `Handler.Main`.
+const (
+	// ExitCodeOK is the exit code for a successful execution.
+	ExitCodeOK = 0
+	// ExitCodeFailure is the exit code for a handler error.
+	ExitCodeFailure = 1
+	// ExitCodeUsage is the exit code for a parsing or usage error.
+	ExitCodeUsage = 2
+)
+
+// ExitCoder is an error which carries a process exit status code.
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
+// ExitError defines an error carrying a custom process exit status code.
+type ExitError struct {
+	// The exit status code.
+	Code int
+	// An optional underlying error. It is not printed when nil.
+	Err error
+}
+
+// Exit creates a new error carrying the given exit status code.
+// It can be returned from application or command handlers to control the `Handler.Main` exit code.
+func Exit(err error, code int) *ExitError {
+	return &ExitError{Code: code, Err: err}
+}
+
+// Error returns the underlying error message.
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit status code.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// Main processes the provided CLI arguments like Run but it returns a process exit status code instead.
+// Errors are printed to stderr, parsing or usage errors exit with code 2 and handler or declaration errors
+// with code 1 unless they implement the `ExitCoder` interface. It is intended to be used as `os.Exit(h.Main(os.Args))`.
+func (h *Handler) Main(vArgs []string) int {
+	return h.MainContext(context.Background(), vArgs)
+}
+
+// MainContext is like Main but it propagates the given context to the handlers.
+func (h *Handler) MainContext(ctx context.Context, vArgs []string) int {
+	code := ExitCodeFailure
+	parsed := true
+	result, err := h.Parse(vArgs)
+	if err != nil {
+		parsed = false
+		// Invalid declarations are programming errors rather than usage ones
+		if !errors.Is(err, helpers.ErrInvalidDeclaration) {
+			code = ExitCodeUsage
+		}
+	} else if err = h.ExecuteContext(ctx, result); err == nil {
+		return ExitCodeOK
+	}
+
+	var exitErr ExitCoder
+	if errors.As(err, &exitErr) {
+		code = exitErr.ExitCode()
+	}
+	var e *ExitError
+	if errors.As(err, &e) && e.Err == nil {
+		return code
+	}
+
+	fmt.Fprintln(h.ap.Stderr(), err)
+	if !parsed && code == ExitCodeUsage && h.ap != nil {
+		name := h.ap.Name
+		if h.ap.MultiCall && len(vArgs) > 0 {
+			name = programBaseName(vArgs[0])
+		}
+		if name != "" {
+			fmt.Fprintf(h.ap.Stderr(), "Run '%s --help' for more information\n", name)
+		}
+	}
+	return code
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+)
+
+func TestHandler_Main(t *testing.T) {
+	tests := []struct {
+		name       string
+		ap         *app.App
+		vargs      []string
+		wantCode   int
+		wantStderr string
+	}{
+		{
+			name:     "should return zero on success",
+			ap:       &app.App{Handler: func(ctx *app.AppContext) error { return nil }},
+			vargs:    []string{"app"},
+			wantCode: ExitCodeOK,
+		},
+		{
+			name:       "should return usage code on unknown flag",
+			ap:         &app.App{Name: "app"},
+			vargs:      []string{"app", "--unknown"},
+			wantCode:   ExitCodeUsage,
+			wantStderr: "error: unknown flag '--unknown' argument\nRun 'app --help' for more information\n",
+		},
+		{
+			name: "should return usage code on invalid flag value",
+			ap: &app.App{
+				Flags: []flag.Flag{flag.FlagInt{Name: "num"}},
+			},
+			vargs:      []string{"app", "--num", "abc"},
+			wantCode:   ExitCodeUsage,
+			wantStderr: "error: invalid integer value for flag '--num'\n",
+		},
+		{
+			name: "should return failure code without usage hint on invalid declarations",
+			ap: &app.App{
+				Name:  "app",
+				Flags: []flag.Flag{flag.FlagInt{}},
+			},
+			vargs:      []string{"app"},
+			wantCode:   ExitCodeFailure,
+			wantStderr: "error: int flag name cannot be empty\n",
+		},
+		{
+			name: "should return failure code on handler error",
+			ap: &app.App{
+				Handler: func(ctx *app.AppContext) error { return errors.New("error: failed") },
+			},
+			vargs:      []string{"app"},
+			wantCode:   ExitCodeFailure,
+			wantStderr: "error: failed\n",
+		},
+		{
+			name: "should honor custom exit codes from app handlers",
+			ap: &app.App{
+				Handler: func(ctx *app.AppContext) error { return Exit(errors.New("error: not found"), 4) },
+			},
+			vargs:      []string{"app"},
+			wantCode:   4,
+			wantStderr: "error: not found\n",
+		},
+		{
+			name: "should honor wrapped custom exit codes from command handlers",
+			ap: &app.App{
+				Commands: []app.Cmd{
+					{
+						Name: "run",
+						Handler: func(ctx *app.CmdContext) error {
+							return fmt.Errorf("error: wrapped: %w", Exit(errors.New("error: failed"), 3))
+						},
+					},
+				},
+			},
+			vargs:      []string{"app", "run"},
+			wantCode:   3,
+			wantStderr: "error: wrapped: error: failed\n",
+		},
+		{
+			name: "should exit silently with custom code and no error",
+			ap: &app.App{
+				Handler: func(ctx *app.AppContext) error { return Exit(nil, 5) },
+			},
+			vargs:    []string{"app"},
+			wantCode: 5,
+		},
+		{
+			name: "should exit silently with wrapped custom code and no error",
+			ap: &app.App{
+				Handler: func(ctx *app.AppContext) error { return fmt.Errorf("error: stopped: %w", Exit(nil, 6)) },
+			},
+			vargs:    []string{"app"},
+			wantCode: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			tt.ap.ErrWriter = &stderr
+			got := New(tt.ap).Main(tt.vargs)
+			assert.Equal(t, tt.wantCode, got)
+			assert.Equal(t, tt.wantStderr, stderr.String())
+		})
+	}
+}
+
+func TestExitError(t *testing.T) {
+	t.Run("should expose the underlying error and code", func(t *testing.T) {
+		inner := errors.New("error: inner")
+		err := Exit(inner, 7)
+		assert.Equal(t, "error: inner", err.Error())
+		assert.Equal(t, 7, err.ExitCode())
+		assert.ErrorIs(t, err, inner)
+	})
+
+	t.Run("should describe the exit status when no error is given", func(t *testing.T) {
+		assert.Equal(t, "exit status 9", Exit(nil, 9).Error())
+	})
+}
+
+func TestHandler_Main_ErrWriter(t *testing.T) {
+	t.Run("should print usage errors to the app error writer", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		ap := &app.App{Name: "app", Writer: &out, ErrWriter: &errOut}
+
+		code := New(ap).Main([]string{"app", "--unknown"})
+		assert.Equal(t, ExitCodeUsage, code)
+		assert.Equal(t, "error: unknown flag '--unknown' argument\nRun 'app --help' for more information\n", errOut.String())
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("should print handler errors to the app error writer", func(t *testing.T) {
+		var errOut bytes.Buffer
+		ap := &app.App{
+			Name:      "app",
+			ErrWriter: &errOut,
+			Handler:   func(ctx *app.AppContext) error { return errors.New("error: failed") },
+		}
+
+		code := New(ap).Main([]string{"app"})
+		assert.Equal(t, ExitCodeFailure, code)
+		assert.Equal(t, "error: failed\n", errOut.String())
+	})
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"slices"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+// valueExpander returns a function which expands the environment variable references of a flag value
+// via a given lookup function when the application enables it, otherwise the values are returned unchanged.
+func valueExpander(ap *app.App, lookup flag.LookupEnvFunc) func(string) string {
+	if !ap.ExpandEnv {
+		return func(s string) string { return s }
+	}
+	return func(s string) string { return helpers.ExpandEnv(s, lookup) }
+}
+
+// expandLookup returns a lookup function which expands the environment variable references
+// of the values found via a given lookup function.
+func expandLookup(lookup flag.LookupEnvFunc) flag.LookupEnvFunc {
+	return func(key string) (string, bool) {
+		v, ok := lookup(key)
+		if ok {
+			v = helpers.ExpandEnv(v, lookup)
+		}
+		return v, ok
+	}
+}
+
+// expandDefaults expands the environment variable references of the application and command
+// flag default values. The flags and commands are copied so the original ones are not modified.
+func expandDefaults(ap *app.App, lookup flag.LookupEnvFunc) {
+	ap.Flags = expandFlagDefaults(ap.Flags, lookup)
+	cmds := make([]app.Cmd, len(ap.Commands))
+	for i, c := range ap.Commands {
+		c.Flags = expandFlagDefaults(c.Flags, lookup)
+		cmds[i] = c
+	}
+	ap.Commands = cmds
+}
+
+// expandFlagDefaults returns a copy of the given flags with their string default values expanded.
+// Path flags are skipped since they always expand their values.
+func expandFlagDefaults(flags []flag.Flag, lookup flag.LookupEnvFunc) []flag.Flag {
+	vflags := slices.Clone(flags)
+	for i, fl := range vflags {
+		switch f := fl.(type) {
+		case flag.FlagString:
+			f.Value = helpers.ExpandEnv(f.Value, lookup)
+			vflags[i] = f
+		case flag.FlagStringSlice:
+			if f.Value != nil {
+				values := make([]string, len(f.Value))
+				for j, v := range f.Value {
+					values[j] = helpers.ExpandEnv(v, lookup)
+				}
+				f.Value = values
+			}
+			vflags[i] = f
+		}
+	}
+	return vflags
+}
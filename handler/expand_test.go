@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+)
+
+func TestHandler_ExpandEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool.json")
+	if err := os.WriteFile(path, []byte(`{"cache": "${CONFIG_DIR}/cache", "jobs": "$N"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	secretPath := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(secretPath, []byte("s3cr3t\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	newApp := func(expand bool, configPaths []string) *app.App {
+		return &app.App{
+			Name:        "tool",
+			ExpandEnv:   expand,
+			ConfigPaths: configPaths,
+			OptsEnvVar:  "TOOL_OPTS",
+			Flags: []flag.Flag{
+				flag.FlagString{Name: "cache", Value: "${XDG_CACHE_HOME:-/tmp}/tool"},
+				flag.FlagStringSlice{Name: "dirs", Value: []string{"$HOME/a", "b"}},
+				flag.FlagInt{Name: "jobs", EnvVar: "TOOL_JOBS"},
+				flag.FlagString{Name: "name", EnvVar: "TOOL_NAME"},
+				flag.FlagString{Name: "token", Secret: true},
+			},
+			Commands: []app.Cmd{
+				{Name: "run", Flags: []flag.Flag{flag.FlagString{Name: "out", Value: "$HOME/out"}}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		expand       bool
+		configPaths  []string
+		env          map[string]string
+		vargs        []string
+		wantCache    string
+		wantDirs     []string
+		wantJobs     int
+		wantName     string
+		wantOut      string
+		wantToken    string
+		wantFile     string
+		wantTailArgs []string
+	}{
+		{
+			name:      "should expand the default values",
+			expand:    true,
+			vargs:     []string{"tool", "run"},
+			wantCache: "/tmp/tool",
+			wantDirs:  []string{"/home/me/a", "b"},
+			wantOut:   "/home/me/out",
+		},
+		{
+			name:      "should expand the environment variable values before validating them",
+			expand:    true,
+			env:       map[string]string{"TOOL_JOBS": "${N}", "TOOL_NAME": "${USER:-anon}"},
+			vargs:     []string{"tool"},
+			wantCache: "/tmp/tool",
+			wantDirs:  []string{"/home/me/a", "b"},
+			wantJobs:  8,
+			wantName:  "anon",
+		},
+		{
+			name:         "should expand the command line values",
+			expand:       true,
+			vargs:        []string{"tool", "--jobs", "$N", "--cache", "$HOME/.cache", "--dirs", "${HOME},c", "$HOME"},
+			wantCache:    "/home/me/.cache",
+			wantDirs:     []string{"/home/me", "c"},
+			wantJobs:     8,
+			wantTailArgs: []string{"$HOME"},
+		},
+		{
+			name:      "should read secret files from the expanded command line paths",
+			expand:    true,
+			vargs:     []string{"tool", "--token-file", "$F"},
+			wantCache: "/tmp/tool",
+			wantDirs:  []string{"/home/me/a", "b"},
+			wantToken: "s3cr3t",
+			wantFile:  secretPath,
+		},
+		{
+			name:      "should read secret files from the expanded default argument paths",
+			expand:    true,
+			env:       map[string]string{"TOOL_OPTS": "--token-file '${F}'"},
+			vargs:     []string{"tool"},
+			wantCache: "/tmp/tool",
+			wantDirs:  []string{"/home/me/a", "b"},
+			wantToken: "s3cr3t",
+			wantFile:  secretPath,
+		},
+		{
+			name:        "should expand the config file values",
+			expand:      true,
+			configPaths: []string{path},
+			vargs:       []string{"tool"},
+			wantCache:   "/etc/tool/cache",
+			wantDirs:    []string{"/home/me/a", "b"},
+			wantJobs:    8,
+		},
+		{
+			name:      "should keep the values verbatim by default",
+			env:       map[string]string{"TOOL_NAME": "$N"},
+			vargs:     []string{"tool", "--cache", "$HOME"},
+			wantCache: "$HOME",
+			wantDirs:  []string{"$HOME/a", "b"},
+			wantName:  "$N",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{"HOME": "/home/me", "N": "8", "CONFIG_DIR": "/etc/tool", "F": secretPath}
+			for k, v := range tt.env {
+				env[k] = v
+			}
+			ap := newApp(tt.expand, tt.configPaths)
+			result, err := NewWithOpts(ap, Options{LookupEnv: flag.LookupEnvMap(env)}).Parse(tt.vargs)
+			assert.NoError(t, err)
+			assert.Equal(t, newApp(tt.expand, tt.configPaths), ap)
+
+			cache, _ := result.Flags.String("cache")
+			assert.Equal(t, tt.wantCache, cache.Value())
+			dirs, _ := result.Flags.StringSlice("dirs")
+			assert.Equal(t, tt.wantDirs, dirs.Value())
+			jobs, _ := result.Flags.Int("jobs")
+			v, err := jobs.Value()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantJobs, v)
+			name, _ := result.Flags.String("name")
+			assert.Equal(t, tt.wantName, name.Value())
+			token, _ := result.Flags.String("token")
+			assert.Equal(t, tt.wantToken, token.Value())
+			assert.Equal(t, tt.wantFile, token.Source().File)
+			if result.CmdFlags != nil {
+				out, _ := result.CmdFlags.String("out")
+				assert.Equal(t, tt.wantOut, out.Value())
+			}
+			assert.ElementsMatch(t, tt.wantTailArgs, result.TailArgs)
+		})
+	}
+}
@@ -2,8 +2,16 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/joseluisq/cline/app"
@@ -16,17 +24,72 @@ import (
 type Handler struct {
 	ap   *app.App
 	opts Options
+
+	// Replaceable hooks for testing purposes (nil values fall back to the OS ones).
+	exit   func(code int)
+	notify func(c chan<- os.Signal, sig ...os.Signal)
 }
 
 // Options represents the configuration options for the Handler.
 type Options struct {
 	MaxArgLen    int
 	MaxArgsCount int
+	// HandleSignals enables the built-in SIGINT and SIGTERM handling which cancels
+	// the handler context, waits for the handler to return and exits with code 130 or 143.
+	HandleSignals bool
+	// GracePeriod is the maximum time to wait for a handler to return after a signal.
+	// A zero value uses a default of 5 seconds.
+	GracePeriod time.Duration
+	// ParseMode controls how flags and positional arguments can be interspersed.
+	// A zero value uses the default mode. See `ParseMode` for the available modes.
+	ParseMode ParseMode
+	// AllowAbbreviations resolves unambiguous prefixes of long flag names (e.g. `--verb` for `--verbose`)
+	// like `getopt_long` does. Ambiguous prefixes are reported as errors listing the candidates.
+	AllowAbbreviations bool
+	// SingleDashLongFlags matches single-dash keys against long names as well as aliases
+	// (e.g. `-file x` for `--file x`) like the standard library `flag` package does.
+	// Keys declared as aliases are still reported as provided via their short name.
+	SingleDashLongFlags bool
+	// ResponseFiles replaces `@path` arguments by the arguments read from the given files
+	// using shell-like quoting and `#` comments. The arguments limits apply after the expansion.
+	ResponseFiles bool
+	// MaxResponseFileDepth is the maximum nesting of response files referring to other ones.
+	// A zero value uses a default of 8.
+	MaxResponseFileDepth int
+	// LookupEnv is an optional function to look up all environment variables (e.g. `flag.LookupEnvMap`).
+	// It takes precedence over `App.LookupEnv` and it defaults to the process environment.
+	LookupEnv flag.LookupEnvFunc
+	// EnvMode defines how invalid environment variable values of the application and selected command flags
+	// are handled (e.g. `helpers.EnvModeStrict`).
+	// It defaults to `helpers.EnvModeIgnore` so the flags fall back to their default values.
+	EnvMode helpers.EnvMode
+	// EnvLogger is an optional logger for the `helpers.EnvModeWarn` warnings.
+	// It defaults to the application error writer.
+	EnvLogger *log.Logger
 }
 
+// ParseMode defines how flags and positional (tail) arguments are interspersed.
+type ParseMode int
+
 const (
-	defaultMaxArgLen    = 256
-	defaultMaxArgsCount = 128
+	// ParseModeDefault stops parsing flags at the first positional argument
+	// and lets a boolean flag consume a following `true` or `false` value.
+	ParseModeDefault ParseMode = iota
+	// ParseModeGNU permutes arguments so flags are parsed anywhere before `--`
+	// and positional arguments are collected as tail arguments.
+	ParseModeGNU
+	// ParseModePOSIX stops parsing flags at the first positional argument
+	// and passes the remaining arguments through verbatim (including `--`).
+	ParseModePOSIX
+	// ParseModePassThrough behaves like ParseModePOSIX but it also passes through verbatim
+	// all arguments after the command word, which suits wrapper tools running a child program.
+	ParseModePassThrough
+)
+
+const (
+	defaultMaxArgLen            = 256
+	defaultMaxArgsCount         = 128
+	defaultMaxResponseFileDepth = 8
 )
 
 // New creates a new handler for the given application with default options.
@@ -52,6 +115,20 @@ func NewWithOpts(ap *app.App, opts Options) *Handler {
 	if opts.MaxArgsCount > 0 {
 		s.MaxArgsCount = opts.MaxArgsCount
 	}
+	s.HandleSignals = opts.HandleSignals
+	s.ParseMode = opts.ParseMode
+	s.AllowAbbreviations = opts.AllowAbbreviations
+	s.SingleDashLongFlags = opts.SingleDashLongFlags
+	s.ResponseFiles = opts.ResponseFiles
+	if opts.MaxResponseFileDepth > 0 {
+		s.MaxResponseFileDepth = opts.MaxResponseFileDepth
+	}
+	s.LookupEnv = opts.LookupEnv
+	s.EnvMode = opts.EnvMode
+	s.EnvLogger = opts.EnvLogger
+	if opts.GracePeriod > 0 {
+		s.GracePeriod = opts.GracePeriod
+	}
 	return &Handler{
 		ap:   ap,
 		opts: *s,
@@ -61,29 +138,131 @@ func NewWithOpts(ap *app.App, opts Options) *Handler {
 // Run processes the provided CLI arguments and executes the appropriate handler.
 // It validates commands and flags, manages tail arguments, and handles special flags like help and version.
 func (h *Handler) Run(vArgs []string) error {
+	return h.RunContext(context.Background(), vArgs)
+}
+
+// RunContext is like Run but it propagates the given context to the application
+// or command handler contexts. See `AppContext.Context` and `CmdContext.Context`.
+func (h *Handler) RunContext(ctx context.Context, vArgs []string) error {
+	result, err := h.Parse(vArgs)
+	if err != nil {
+		return err
+	}
+	return h.ExecuteContext(ctx, result)
+}
+
+// ParseResult holds the outcome of parsing CLI arguments against an application declaration.
+type ParseResult struct {
+	// It references to a copy of the application with its flags and commands resolved.
+	App *app.App
+	// It references to the selected command (if any) with its flags resolved.
+	Cmd *app.Cmd
+	// It references to the application flag values.
+	Flags *flag.FlagValues
+	// It references to the selected command flag values (if any).
+	CmdFlags *flag.FlagValues
+	// It contains the tail input arguments.
+	TailArgs []string
+	// It reports whether the help flag was provided.
+	Help bool
+	// It reports whether the version flag was provided.
+	Version bool
+	// It contains the unrecognized command name handled by `App.NotFound` (if any).
+	NotFound string
+	// It references to the external plugin command selected (if any).
+	Plugin *helpers.Plugin
+	// It contains the invoked program name which is the base name of `argv[0]`
+	// for multi-call applications or the application name otherwise.
+	ProgramName string
+	// It contains the variables loaded from the application dotenv files (if any).
+	Env map[string]string
+	// It references to the function to look up the environment variables including the dotenv files ones.
+	LookupEnv flag.LookupEnvFunc
+	// It contains the output format requested via the built-in print config flag (if any).
+	PrintConfig string
+}
+
+// Parse processes the provided CLI arguments without executing any handler.
+// It validates commands and flags and resolves their values without mutating the application declaration,
+// so the same handler can parse arguments many times and concurrently.
+// Resolving the values may read files (response, dotenv, config and secret files), consume the application
+// input reader for secret values and create temporary files to check the writable path flags.
+func (h *Handler) Parse(vArgs []string) (*ParseResult, error) {
+	if h.ap == nil {
+		return nil, &helpers.ParseError{
+			Kind:    helpers.KindInvalidDeclaration,
+			Index:   -1,
+			Message: "error: application instance not found",
+		}
+	}
+
+	// Expand response files before applying the arguments limits
+	if h.opts.ResponseFiles {
+		maxDepth := h.opts.MaxResponseFileDepth
+		if maxDepth <= 0 {
+			maxDepth = defaultMaxResponseFileDepth
+		}
+		expanded, err := helpers.ExpandResponseFiles(vArgs, maxDepth)
+		if err != nil {
+			return nil, err
+		}
+		vArgs = expanded
+	}
+
 	// Commands and flags validation
 	var vArgsLen = len(vArgs)
-	if vArgsLen > h.opts.MaxArgsCount {
-		return fmt.Errorf("error: number of arguments exceeds the limit of %d", h.opts.MaxArgsCount)
+	if err := h.checkArgsCount(vArgsLen); err != nil {
+		return nil, err
+	}
+
+	// Work on a copy so the application declaration is never mutated
+	ap := *h.ap
+	applyEnvPrefix(&ap)
+
+	// Load the dotenv files feeding the flag environment variables
+	env, lookup, err := loadEnvFiles(&ap, h.lookupEnv())
+	if err != nil {
+		return nil, err
+	}
+
+	// Expand the flag default values and the environment variable values before validating them
+	flagLookup := lookup
+	if ap.ExpandEnv {
+		expandDefaults(&ap, lookup)
+		flagLookup = expandLookup(lookup)
 	}
+	expand := valueExpander(&ap, lookup)
 
 	// 1. Check application global flags
-	vflags, err := helpers.ValidateFlagsAndInit(h.ap.Flags)
+	// The invalid environment variable values are checked once the command is selected
+	vflags, err := helpers.ValidateFlagsAndInitEnv(ap.Flags, helpers.EnvOptions{Lookup: flagLookup})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	h.ap.Flags = vflags
+	ap.Flags = vflags
 
 	// 2. Check commands and their flags
-	vcmds, err := helpers.ValidateCommands(h.ap.Commands)
+	vcmds, err := helpers.ValidateCommandsEnv(ap.Commands, helpers.EnvOptions{Lookup: flagLookup})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	h.ap.Commands = vcmds
+	ap.Commands = vcmds
 
-	var appFlagMap = helpers.BuildFlagMap(h.ap.Flags)
-	var cmdFlagMaps = make(map[string]map[string]helpers.FlagInfo, len(h.ap.Commands))
-	for _, cmd := range h.ap.Commands {
+	var defaultCmd *app.Cmd
+	if ap.DefaultCommand != "" {
+		if _, defaultCmd = helpers.FindCommand(ap.DefaultCommand, ap.Commands); defaultCmd == nil {
+			return nil, &helpers.ParseError{
+				Kind:    helpers.KindInvalidDeclaration,
+				Index:   -1,
+				Command: ap.DefaultCommand,
+				Message: fmt.Sprintf("error: default command '%s' is not declared", ap.DefaultCommand),
+			}
+		}
+	}
+
+	var appFlagMap = helpers.BuildFlagMap(ap.Flags)
+	var cmdFlagMaps = make(map[string]map[string]helpers.FlagInfo, len(ap.Commands))
+	for _, cmd := range ap.Commands {
 		cmdFlagMaps[cmd.Name] = helpers.BuildFlagMap(cmd.Flags)
 	}
 
@@ -91,51 +270,94 @@ func (h *Handler) Run(vArgs []string) error {
 	var lastCmd *app.Cmd
 	var lastFlag flag.Flag
 	var lastFlagIndex = -1
+	var lastFlagArgIndex = -1
+	var lastFlagFile = false
 	var tailArgs = make([]string, 0, 4)
 	var hasCmd = false
 	var hasHelp = false
 	var hasVersion = false
+	var notFound = ""
+	var plugin *helpers.Plugin
+	var printConfig = ""
+	var mode = h.opts.ParseMode
+	var passThrough = false
+
+	// Select a command and set the flag values of its default arguments from the command environment variable
+	var envArgsLen = 0
+	selectCmd := func(c *app.Cmd) error {
+		hasCmd = true
+		lastCmd = c
+		if vArgsLen == 0 || ap.OptsEnvVar == "" {
+			return nil
+		}
+		envVar := cmdOptsEnvVar(ap.OptsEnvVar, c.Name)
+		n, err := h.applyEnvArgs(c.Flags, cmdFlagMaps[c.Name], envVar, lookup, ap.Stdin(), expand)
+		if err != nil {
+			var perr *helpers.ParseError
+			if errors.As(err, &perr) {
+				perr.Command = c.Name
+			}
+			return err
+		}
+		envArgsLen += n
+		return h.checkArgsCount(vArgsLen + envArgsLen)
+	}
+
+	// Multi-call binary: select the command via the program name
+	var programName = ap.Name
+	if ap.MultiCall && vArgsLen > 0 {
+		programName = programBaseName(vArgs[0])
+		if _, c := helpers.FindCommand(programName, ap.Commands); c != nil {
+			if err := selectCmd(c); err != nil {
+				return nil, err
+			}
+			passThrough = mode == ParseModePassThrough
+		}
+	}
+
+	// Set the flag values of the default arguments from the application environment variable
+	if vArgsLen > 0 && ap.OptsEnvVar != "" && !hasCmd {
+		n, err := h.applyEnvArgs(ap.Flags, appFlagMap, ap.OptsEnvVar, lookup, ap.Stdin(), expand)
+		if err != nil {
+			return nil, err
+		}
+		envArgsLen += n
+		if err := h.checkArgsCount(vArgsLen + envArgsLen); err != nil {
+			return nil, err
+		}
+	}
 
 	for idx := 1; idx < vArgsLen; idx++ {
 		arg := strings.TrimSpace(vArgs[idx])
 
 		if len(arg) > h.opts.MaxArgLen {
-			return fmt.Errorf("error: argument exceeds maximum length of %d characters", h.opts.MaxArgLen)
+			return nil, parseError(
+				helpers.KindLimitExceeded, idx, arg,
+				"error: argument exceeds maximum length of %d characters", h.opts.MaxArgLen,
+			)
 		}
 
 		if !utf8.ValidString(arg) {
-			return fmt.Errorf("error: argument contains invalid UTF-8 characters")
+			return nil, parseError(helpers.KindInvalidArgument, idx, arg, "error: argument contains invalid UTF-8 characters")
 		}
 
-		// Check if the previous flag was expecting a value but didn't get one
-		if lastFlag != nil {
-			// Check if the flag type requires a value (i.e., it's not a bool)
-			name := ""
-			isUnassignedValueFlag := false
-			switch v := lastFlag.(type) {
-			case flag.FlagInt:
-				if !v.FlagAssigned {
-					name = v.Name
-					isUnassignedValueFlag = true
-				}
-			case flag.FlagString:
-				if !v.FlagAssigned {
-					name = v.Name
-					isUnassignedValueFlag = true
-				}
-			case flag.FlagStringSlice:
-				if !v.FlagAssigned {
-					name = v.Name
-					isUnassignedValueFlag = true
-				}
-			}
+		// Pass the remaining arguments through verbatim after the first positional argument
+		// or after the command word depending on the parsing mode
+		if len(tailArgs) > 0 && (mode == ParseModePOSIX || mode == ParseModePassThrough) {
+			passThrough = true
+		}
+		if passThrough {
+			tailArgs = append(tailArgs, vArgs[idx])
+			continue
+		}
 
-			// If the previous flag needed a value and the current argument is another flag,
-			// it's an error.
-			if isUnassignedValueFlag && strings.HasPrefix(arg, "-") {
-				// The previous flag is missing its required value.
-				return fmt.Errorf("error: flag '--%s' requires a value", name)
-			}
+		// Check if the previous flag was expecting a value but didn't get one.
+		// If the previous flag needed a value and the current argument is another flag, it's an error.
+		// A single dash is the standard input value of a secret flag.
+		stdinValue := arg == "-" && isSecretPending(lastFlag)
+		if isValuePending(lastFlag) && strings.HasPrefix(arg, "-") && !stdinValue {
+			name, _ := helpers.FlagNames(lastFlag)
+			return nil, missingValueError(name, lastFlagArgIndex, vArgs)
 		}
 
 		// Check for no supported arguments (remaining)
@@ -146,13 +368,13 @@ func (h *Handler) Run(vArgs []string) error {
 			break
 		}
 
-		if len(tailArgs) > 0 {
+		if len(tailArgs) > 0 && mode != ParseModeGNU {
 			tailArgs = append(tailArgs, arg)
 			continue
 		}
 
 		// 3.1. Flags (options)
-		if strings.HasPrefix(arg, "-") {
+		if strings.HasPrefix(arg, "-") && !stdinValue {
 			var flagKey string
 			isAlias := !strings.HasPrefix(arg, "--")
 			if isAlias {
@@ -162,7 +384,48 @@ func (h *Handler) Run(vArgs []string) error {
 			}
 
 			if err := helpers.IsValidToken(flagKey, "flag"); err != nil {
-				return err
+				perr := parseError(helpers.KindInvalidArgument, idx, arg, "%s", err.Error())
+				perr.Flag = flagKey
+				return nil, perr
+			}
+
+			// Flags in scope which are the command ones or the application ones plus the default command ones
+			var scope = [][]flag.Flag{ap.Flags}
+			if hasCmd {
+				scope = [][]flag.Flag{lastCmd.Flags}
+			} else if defaultCmd != nil {
+				scope = append(scope, defaultCmd.Flags)
+			}
+
+			// Match single-dash keys against long names when they are not aliases
+			if isAlias && h.opts.SingleDashLongFlags && len(flagKey) > 0 && !isDeclaredAlias(flagKey, hasCmd, scope...) {
+				isAlias = false
+			}
+
+			// Resolve long flag abbreviations
+			var abbreviated = false
+			if !isAlias && h.opts.AllowAbbreviations && flagKey != "" && !strings.HasPrefix(flagKey, "-") {
+				matches := abbreviationCandidates(flagKey, hasCmd, ap.PrintConfig, scope...)
+				if len(matches) > 1 {
+					candidates := make([]string, len(matches))
+					for i, m := range matches {
+						candidates[i] = "--" + m
+					}
+					err := parseError(
+						helpers.KindAmbiguousFlag, idx, arg,
+						"error: ambiguous flag '%s' argument (could be %s)", arg, quoteList(candidates),
+					)
+					err.Flag = flagKey
+					err.Suggestions = candidates
+					if hasCmd {
+						err.Command = lastCmd.Name
+					}
+					return nil, err
+				}
+				if len(matches) == 1 && matches[0] != flagKey {
+					flagKey = matches[0]
+					abbreviated = true
+				}
 			}
 
 			// Process special flags (help and version)
@@ -184,6 +447,17 @@ func (h *Handler) Run(vArgs []string) error {
 				}
 			}
 
+			// Process the built-in print config flag with its optional format
+			if ap.PrintConfig && flagKey == "print-config" {
+				printConfig = print.FormatTable
+				if idx+1 < vArgsLen && (vArgs[idx+1] == print.FormatTable || vArgs[idx+1] == print.FormatJSON) {
+					printConfig = vArgs[idx+1]
+					idx++
+				}
+				lastFlag = nil
+				continue
+			}
+
 			// Skip unsupported flags
 			if flagKey == "" || strings.HasPrefix(flagKey, "-") {
 				tailArgs = append(tailArgs, arg)
@@ -201,29 +475,61 @@ func (h *Handler) Run(vArgs []string) error {
 			}
 
 			flagInfo, ok := flagMap[flagKey]
+			if !ok && !hasCmd && defaultCmd != nil {
+				// A default command flag selects the default command
+				if info, found := cmdFlagMaps[defaultCmd.Name][flagKey]; found {
+					if err := selectCmd(defaultCmd); err != nil {
+						return nil, err
+					}
+					flagInfo, ok = info, true
+				}
+			}
 			if !ok {
-				return fmt.Errorf("error: unknown flag '%s' argument", arg)
+				err := parseError(helpers.KindUnknownFlag, idx, arg, "error: unknown flag '%s' argument", arg)
+				err.Flag = flagKey
+				flags := ap.Flags
+				if hasCmd {
+					err.Command = lastCmd.Name
+					flags = lastCmd.Flags
+				}
+				return nil, withSuggestions(err, flagSuggestions(flagKey, isAlias, flags, hasCmd, ap.PrintConfig))
 			}
 			lastFlag = flagInfo.Flag
 			lastFlagIndex = flagInfo.Index
+			lastFlagArgIndex = idx
+			lastFlagFile = flagInfo.File
 
 			// Check provided incoming flags
 			switch v := lastFlag.(type) {
 			case flag.FlagBool:
 				v.FlagProvided = true
 				v.FlagProvidedAsAlias = isAlias
+				v.FlagProvidedAsAbbrev = abbreviated
+				v.FlagSource = flag.Source{Kind: flag.SourceArg, Index: idx}
 				lastFlag = v
 			case flag.FlagInt:
 				v.FlagProvided = true
 				v.FlagProvidedAsAlias = isAlias
+				v.FlagProvidedAsAbbrev = abbreviated
+				v.FlagSource = flag.Source{Kind: flag.SourceArg, Index: idx}
 				lastFlag = v
 			case flag.FlagString:
 				v.FlagProvided = true
 				v.FlagProvidedAsAlias = isAlias
+				v.FlagProvidedAsAbbrev = abbreviated
+				v.FlagSource = flag.Source{Kind: flag.SourceArg, Index: idx}
 				lastFlag = v
 			case flag.FlagStringSlice:
 				v.FlagProvided = true
 				v.FlagProvidedAsAlias = isAlias
+				v.FlagProvidedAsAbbrev = abbreviated
+				v.FlagSource = flag.Source{Kind: flag.SourceArg, Index: idx}
+				lastFlag = v
+			case flag.FlagPath:
+				v.FlagProvided = true
+				v.FlagProvidedAsAlias = isAlias
+				v.FlagProvidedAsAbbrev = abbreviated
+				v.FlagSource = flag.Source{Kind: flag.SourceArg, Index: idx}
 				lastFlag = v
 			}
 
@@ -241,7 +547,7 @@ func (h *Handler) Run(vArgs []string) error {
 					fl.FlagAssigned = true
 
 					// Check if the next argument could be a value for this bool flag
-					if idx+1 < vArgsLen {
+					if mode == ParseModeDefault && idx+1 < vArgsLen {
 						nextArg := vArgs[idx+1]
 						if _, err := flag.Value(nextArg).ToBool(); err == nil {
 							fl.FlagValue = flag.Value(nextArg)
@@ -257,8 +563,8 @@ func (h *Handler) Run(vArgs []string) error {
 							lastCmd.Flags[lastFlagIndex] = fl
 						}
 					} else {
-						if len(h.ap.Flags) > 0 && lastFlagIndex > -1 {
-							h.ap.Flags[lastFlagIndex] = fl
+						if len(ap.Flags) > 0 && lastFlagIndex > -1 {
+							ap.Flags[lastFlagIndex] = fl
 						}
 					}
 					continue
@@ -270,19 +576,46 @@ func (h *Handler) Run(vArgs []string) error {
 
 		// 3.2. Commands
 		// 3.2.1 Check for a valid command (first time)
-		if !hasCmd {
-			for i, c := range h.ap.Commands {
-				if c.Name == arg {
-					hasCmd = true
-					lastCmd = &h.ap.Commands[i]
-					break
+		if !hasCmd && len(tailArgs) == 0 {
+			if _, c := helpers.FindCommand(arg, ap.Commands); c != nil {
+				if err := selectCmd(c); err != nil {
+					return nil, err
 				}
-			}
-			if hasCmd {
+				passThrough = mode == ParseModePassThrough
 				continue
 			}
 		}
 
+		// 3.2.2 Check for an external plugin command
+		if !hasCmd && len(tailArgs) == 0 && ap.Plugins && !isValuePending(lastFlag) {
+			if p, ok := helpers.LookupPlugin(&ap, arg, lookup); ok {
+				plugin = &p
+				tailArgs = append(tailArgs, vArgs[idx+1:]...)
+				break
+			}
+		}
+
+		// 3.2.3 Route positional arguments to the default command
+		if !hasCmd && len(tailArgs) == 0 && defaultCmd != nil && !isValuePending(lastFlag) {
+			if err := selectCmd(defaultCmd); err != nil {
+				return nil, err
+			}
+		}
+
+		// 3.2.4 Check for an unknown or mistyped command
+		if !hasCmd && len(tailArgs) == 0 && len(ap.Commands) > 0 && !isValuePending(lastFlag) {
+			if ap.NotFound != nil {
+				notFound = arg
+				tailArgs = append(tailArgs, vArgs[idx+1:]...)
+				break
+			}
+			if ap.StrictCommands {
+				err := parseError(helpers.KindUnknownCommand, idx, arg, "error: unknown command '%s'", arg)
+				err.Command = arg
+				return nil, withSuggestions(err, commandSuggestions(arg, ap.Commands))
+			}
+		}
+
 		// 4. If there is no command found assume it as a tail arg
 		if lastFlag == nil {
 			tailArgs = append(tailArgs, arg)
@@ -304,8 +637,9 @@ func (h *Handler) Run(vArgs []string) error {
 					tailArgs = append(tailArgs, arg)
 					continue
 				}
-				s := flag.Value(arg)
-				if _, err := s.ToInt(); err == nil {
+				s := flag.Value(expand(arg))
+				_, errInt := s.ToInt()
+				if errInt == nil {
 					fl.FlagValue = s
 					fl.FlagAssigned = true
 					lastFlag = fl
@@ -315,13 +649,16 @@ func (h *Handler) Run(vArgs []string) error {
 							lastCmd.Flags[lastFlagIndex] = fl
 						}
 					} else {
-						if len(h.ap.Flags) > 0 && lastFlagIndex > -1 {
-							h.ap.Flags[lastFlagIndex] = fl
+						if len(ap.Flags) > 0 && lastFlagIndex > -1 {
+							ap.Flags[lastFlagIndex] = fl
 						}
 					}
 					continue
 				} else {
-					return fmt.Errorf("error: invalid integer value for flag '--%s'", fl.Name)
+					err := parseError(helpers.KindInvalidValue, idx, arg, "error: invalid integer value for flag '--%s'", fl.Name)
+					err.Flag = fl.Name
+					err.Err = errInt
+					return nil, err
 				}
 			}
 		case flag.FlagString:
@@ -330,7 +667,20 @@ func (h *Handler) Run(vArgs []string) error {
 					tailArgs = append(tailArgs, arg)
 					continue
 				}
-				fl.FlagValue = flag.Value(arg)
+				fl.FlagValue = flag.Value(expand(arg))
+				if path := fl.FlagValue.ToString(); fl.Secret && (lastFlagFile || path == "-") {
+					value, err := readSecret(ap.Stdin(), path)
+					if err != nil {
+						perr := parseError(helpers.KindInvalidValue, idx, arg, "error: unable to read secret value of flag '--%s': %s", fl.Name, err)
+						perr.Flag = fl.Name
+						perr.Err = err
+						return nil, perr
+					}
+					fl.FlagValue = flag.Value(value)
+					if path != "-" {
+						fl.FlagSource.File = path
+					}
+				}
 				fl.FlagAssigned = true
 				lastFlag = fl
 
@@ -339,13 +689,34 @@ func (h *Handler) Run(vArgs []string) error {
 						lastCmd.Flags[lastFlagIndex] = fl
 					}
 				} else {
-					if len(h.ap.Flags) > 0 && lastFlagIndex > -1 {
-						h.ap.Flags[lastFlagIndex] = fl
+					if len(ap.Flags) > 0 && lastFlagIndex > -1 {
+						ap.Flags[lastFlagIndex] = fl
 					}
 				}
 				continue
 			}
 		case flag.FlagStringSlice:
+			if fl.Name != "" {
+				if fl.FlagAssigned {
+					tailArgs = append(tailArgs, arg)
+					continue
+				}
+				fl.FlagValue = flag.Value(expand(arg))
+				fl.FlagAssigned = true
+				lastFlag = fl
+
+				if hasCmd {
+					if len(lastCmd.Flags) > 0 && lastFlagIndex > -1 {
+						lastCmd.Flags[lastFlagIndex] = fl
+					}
+				} else {
+					if len(ap.Flags) > 0 && lastFlagIndex > -1 {
+						ap.Flags[lastFlagIndex] = fl
+					}
+				}
+				continue
+			}
+		case flag.FlagPath:
 			if fl.Name != "" {
 				if fl.FlagAssigned {
 					tailArgs = append(tailArgs, arg)
@@ -360,8 +731,8 @@ func (h *Handler) Run(vArgs []string) error {
 						lastCmd.Flags[lastFlagIndex] = fl
 					}
 				} else {
-					if len(h.ap.Flags) > 0 && lastFlagIndex > -1 {
-						h.ap.Flags[lastFlagIndex] = fl
+					if len(ap.Flags) > 0 && lastFlagIndex > -1 {
+						ap.Flags[lastFlagIndex] = fl
 					}
 				}
 				continue
@@ -375,56 +746,285 @@ func (h *Handler) Run(vArgs []string) error {
 		switch v := lastFlag.(type) {
 		case flag.FlagInt:
 			if !v.FlagAssigned {
-				return fmt.Errorf("error: flag '--%s' requires a value", v.Name)
+				return nil, missingValueError(v.Name, lastFlagArgIndex, vArgs)
 			}
 		case flag.FlagString:
 			if !v.FlagAssigned {
-				return fmt.Errorf("error: flag '--%s' requires a value", v.Name)
+				return nil, missingValueError(v.Name, lastFlagArgIndex, vArgs)
 			}
 		case flag.FlagStringSlice:
 			if !v.FlagAssigned {
-				return fmt.Errorf("error: flag '--%s' requires a value", v.Name)
+				return nil, missingValueError(v.Name, lastFlagArgIndex, vArgs)
+			}
+		case flag.FlagPath:
+			if !v.FlagAssigned {
+				return nil, missingValueError(v.Name, lastFlagArgIndex, vArgs)
 			}
 		}
 	}
 
-	// Show `help` flag details
-	if hasHelp {
-		if hasCmd {
-			return print.PrintHelp(h.ap, lastCmd)
+	// Fall back to the default command when no command was provided
+	if !hasCmd && defaultCmd != nil && plugin == nil && notFound == "" && !hasHelp && !hasVersion {
+		if err := selectCmd(defaultCmd); err != nil {
+			return nil, err
+		}
+	}
+
+	var cmd *app.Cmd
+	if hasCmd {
+		cmd = lastCmd
+	}
+
+	// Check the environment variable values of the application and selected command flags
+	// then read their secret values of the `_FILE` environment variables
+	if !hasHelp && !hasVersion {
+		if err := h.checkEnv(&ap, cmd, flagLookup); err != nil {
+			return nil, err
 		}
-		return print.PrintHelp(h.ap, nil)
+		if err := applySecretEnv(&ap, cmd, flagLookup); err != nil {
+			return nil, err
+		}
+	}
+
+	// Merge the config file values of the flags not provided otherwise
+	if !hasHelp && !hasVersion && (ap.ConfigFlag != "" || len(ap.ConfigPaths) > 0) {
+		if err := applyConfig(&ap, cmd, lookup, expand); err != nil {
+			return nil, err
+		}
+	}
+
+	// Expand and check the path flag values
+	if !hasHelp && !hasVersion {
+		if err := resolvePaths(&ap, cmd, lookup); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &ParseResult{
+		App:         &ap,
+		Flags:       flag.NewFlagValues(ap.Flags),
+		TailArgs:    tailArgs,
+		Help:        hasHelp,
+		Version:     hasVersion,
+		NotFound:    notFound,
+		Plugin:      plugin,
+		ProgramName: programName,
+		Env:         env,
+		LookupEnv:   lookup,
+		PrintConfig: printConfig,
+	}
+	if hasCmd {
+		result.Cmd = lastCmd
+		result.CmdFlags = flag.NewFlagValues(lastCmd.Flags)
+	}
+	return result, nil
+}
+
+// Execute runs the appropriate handler for a given parse result.
+// It also handles special flags like help and version.
+func (h *Handler) Execute(result *ParseResult) error {
+	return h.ExecuteContext(context.Background(), result)
+}
+
+// ExecuteContext is like Execute but it propagates the given context to the handlers.
+func (h *Handler) ExecuteContext(ctx context.Context, result *ParseResult) error {
+	if result == nil || result.App == nil {
+		return fmt.Errorf("error: parse result not found")
+	}
+	ap := result.App
+	cmd := result.Cmd
+
+	// Show `help` flag details
+	if result.Help {
+		return print.PrintHelpProgram(ap, cmd, usageName(result), result.LookupEnv)
 	}
 
 	// Show `version` flag details
-	if hasVersion {
-		h.ap.PrintVersion()
+	if result.Version {
+		ap.PrintVersion()
 		return nil
 	}
 
+	// Show the effective flag values and their sources
+	if result.PrintConfig != "" {
+		return print.PrintConfig(ap, cmd, result.PrintConfig)
+	}
+
+	// Export the dotenv files variables to the process environment
+	if ap.EnvSetenv {
+		if err := exportEnv(result.Env, ap.EnvOverride); err != nil {
+			return err
+		}
+	}
+
 	// Call command handler
-	if hasCmd && lastCmd.Handler != nil {
-		return lastCmd.Handler(&app.CmdContext{
-			Cmd:      lastCmd,
-			Flags:    flag.NewFlagValues(lastCmd.Flags),
-			TailArgs: tailArgs,
-			AppContext: app.NewContext(
-				h.ap,
-				flag.NewFlagValues(h.ap.Flags),
-				[]string{},
-			),
+	if cmd != nil && cmd.Handler != nil {
+		return h.invoke(ctx, func(ctx context.Context) error {
+			return cmd.Handler(&app.CmdContext{
+				Cmd:        cmd,
+				Flags:      result.CmdFlags,
+				TailArgs:   result.TailArgs,
+				AppContext: app.NewContext(ap, result.Flags, []string{}).WithContext(ctx).WithLookupEnv(result.LookupEnv),
+			})
+		})
+	}
+
+	// Call an external plugin command
+	if result.Plugin != nil {
+		return h.invoke(ctx, func(ctx context.Context) error {
+			return runPlugin(ctx, result)
+		})
+	}
+
+	// Call the handler of an unrecognized command
+	if result.NotFound != "" && ap.NotFound != nil {
+		return h.invoke(ctx, func(ctx context.Context) error {
+			return ap.NotFound(
+				app.NewContext(ap, result.Flags, result.TailArgs).WithContext(ctx).WithLookupEnv(result.LookupEnv),
+				result.NotFound,
+				result.TailArgs,
+			)
 		})
 	}
 
 	// Call application handler
-	if h.ap.Handler != nil {
-		ctx := app.NewContext(
-			h.ap,
-			flag.NewFlagValues(h.ap.Flags),
-			tailArgs,
-		)
-		return h.ap.Handler(ctx)
+	if ap.Handler != nil {
+		return h.invoke(ctx, func(ctx context.Context) error {
+			return ap.Handler(
+				app.NewContext(ap, result.Flags, result.TailArgs).WithContext(ctx).WithLookupEnv(result.LookupEnv),
+			)
+		})
+	}
+
+	return nil
+}
+
+// lookupEnv returns the function to look up environment variables of the handler options,
+// the application or the process environment.
+func (h *Handler) lookupEnv() flag.LookupEnvFunc {
+	if h.opts.LookupEnv != nil {
+		return h.opts.LookupEnv
+	}
+	if h.ap != nil && h.ap.LookupEnv != nil {
+		return h.ap.LookupEnv
+	}
+	return os.LookupEnv
+}
+
+// envOptions returns the flag environment variables options of a given application and lookup function.
+func (h *Handler) envOptions(ap *app.App, lookup flag.LookupEnvFunc) helpers.EnvOptions {
+	logger := h.opts.EnvLogger
+	if logger == nil && h.opts.EnvMode == helpers.EnvModeWarn {
+		logger = log.New(ap.Stderr(), "", 0)
 	}
+	return helpers.EnvOptions{Lookup: lookup, Mode: h.opts.EnvMode, Logger: logger}
+}
 
+// checkEnv handles the invalid environment variable values of the application flags
+// and the selected command flags (if any) according to the handler options.
+func (h *Handler) checkEnv(ap *app.App, cmd *app.Cmd, lookup flag.LookupEnvFunc) error {
+	opts := h.envOptions(ap, lookup)
+	if err := helpers.CheckFlagsEnv(ap.Flags, opts); err != nil {
+		return err
+	}
+	if cmd == nil {
+		return nil
+	}
+	if err := helpers.CheckFlagsEnv(cmd.Flags, opts); err != nil {
+		var perr *helpers.ParseError
+		if errors.As(err, &perr) {
+			perr.Command = cmd.Name
+		}
+		return err
+	}
 	return nil
 }
+
+// checkArgsCount checks if a given number of arguments exceeds the limit.
+func (h *Handler) checkArgsCount(n int) error {
+	if n > h.opts.MaxArgsCount {
+		return parseError(
+			helpers.KindLimitExceeded, h.opts.MaxArgsCount, "",
+			"error: number of arguments exceeds the limit of %d", h.opts.MaxArgsCount,
+		)
+	}
+	return nil
+}
+
+// parseError creates a new parse error of the given kind for an input argument.
+func parseError(kind helpers.ErrorKind, index int, token string, format string, a ...any) *helpers.ParseError {
+	return &helpers.ParseError{
+		Kind:    kind,
+		Index:   index,
+		Token:   token,
+		Message: fmt.Sprintf(format, a...),
+	}
+}
+
+// programBaseName returns the program name of a given `argv[0]` without its directory and `.exe` extension.
+func programBaseName(arg0 string) string {
+	name := filepath.Base(arg0)
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name
+}
+
+// usageName returns the program invocation used in help messages (e.g. `tool info`).
+// Commands selected via the program name of multi-call applications are invoked by the program name itself.
+func usageName(result *ParseResult) string {
+	name := result.ProgramName
+	if name == "" {
+		name = result.App.Name
+	}
+	if cmd := result.Cmd; cmd != nil {
+		if result.App.MultiCall && (cmd.Name == name || slices.Contains(cmd.Aliases, name)) {
+			return name
+		}
+		return name + " " + cmd.Name
+	}
+	return name
+}
+
+// isValuePending checks if a given flag requires a value (i.e., it's not a bool) but it was not assigned yet.
+func isValuePending(fl flag.Flag) bool {
+	switch v := fl.(type) {
+	case flag.FlagInt:
+		return !v.FlagAssigned
+	case flag.FlagString:
+		return !v.FlagAssigned
+	case flag.FlagStringSlice:
+		return !v.FlagAssigned
+	case flag.FlagPath:
+		return !v.FlagAssigned
+	}
+	return false
+}
+
+// isSecretPending checks if a given flag is a secret string flag expecting a value.
+func isSecretPending(fl flag.Flag) bool {
+	f, ok := fl.(flag.FlagString)
+	return ok && f.Secret && !f.FlagAssigned
+}
+
+// isDeclaredAlias checks if a given key is an alias of the given flags or a built-in flag alias.
+func isDeclaredAlias(key string, isCmd bool, flags ...[]flag.Flag) bool {
+	if key == "h" || (key == "v" && !isCmd) {
+		return true
+	}
+	for _, fls := range flags {
+		for _, fl := range fls {
+			if _, aliases := helpers.FlagNames(fl); slices.Contains(aliases, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// missingValueError creates a new parse error for a flag argument which requires a value.
+func missingValueError(name string, index int, vArgs []string) *helpers.ParseError {
+	err := parseError(helpers.KindMissingValue, index, vArgs[index], "error: flag '--%s' requires a value", name)
+	err.Flag = name
+	return err
+}
@@ -3,12 +3,17 @@ package handler
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/joseluisq/cline/app"
 	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
 )
 
 func TestHandler_Run(t *testing.T) {
@@ -601,7 +606,7 @@ func TestHandler_Run(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			if actualErr := New(tt.ap).Run(tt.vargs); tt.wantErr != nil {
 				assert.Error(t, actualErr, "Expected an error but got none")
-				assert.Equal(t, actualErr, tt.wantErr, "Error message does not match the expected one")
+				assert.EqualError(t, actualErr, tt.wantErr.Error(), "Error message does not match the expected one")
 			} else {
 				assert.NoError(t, actualErr, "Expected no error but got one")
 			}
@@ -622,12 +627,14 @@ func TestNewWithOpts(t *testing.T) {
 			opts: Options{
 				MaxArgLen:    8192,
 				MaxArgsCount: 2048,
+				ParseMode:    ParseModeGNU,
 			},
 			want: &Handler{
 				ap: &app.App{Name: "TestApp"},
 				opts: Options{
 					MaxArgLen:    8192,
 					MaxArgsCount: 2048,
+					ParseMode:    ParseModeGNU,
 				},
 			},
 		},
@@ -651,3 +658,779 @@ func TestNewWithOpts(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_Parse(t *testing.T) {
+	newApp := func() *app.App {
+		return &app.App{
+			Name: "app",
+			Flags: []flag.Flag{
+				flag.FlagBool{Name: "verbose", Aliases: []string{"V"}},
+				flag.FlagString{Name: "file", Value: ".env"},
+			},
+			Commands: []app.Cmd{
+				{
+					Name: "run",
+					Flags: []flag.Flag{
+						flag.FlagInt{Name: "workers", Value: 1},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("should return the selected command, flag values and tail args", func(t *testing.T) {
+		h := New(newApp())
+		result, err := h.Parse([]string{"app", "-V", "--file", "a.env", "run", "--workers", "4", "x"})
+		assert.NoError(t, err)
+		assert.Equal(t, "run", result.Cmd.Name)
+		assert.Equal(t, []string{"x"}, result.TailArgs)
+		assert.False(t, result.Help)
+		assert.False(t, result.Version)
+
+		verbose, err := result.Flags.Bool("verbose")
+		assert.NoError(t, err)
+		assert.True(t, verbose.IsProvidedShort())
+		assert.Equal(t, flag.Value("a.env"), result.Flags.Value("file"))
+
+		workers, err := result.CmdFlags.Int("workers")
+		assert.NoError(t, err)
+		n, _ := workers.Value()
+		assert.Equal(t, 4, n)
+	})
+
+	t.Run("should not mutate the application declaration", func(t *testing.T) {
+		ap := newApp()
+		want := newApp()
+		h := New(ap)
+
+		_, err := h.Parse([]string{"app", "--verbose", "--file", "a.env", "run", "--workers", "4"})
+		assert.NoError(t, err)
+		assert.Equal(t, want.Flags, ap.Flags)
+		assert.Equal(t, want.Commands[0].Flags, ap.Commands[0].Flags)
+
+		result, err := h.Parse([]string{"app"})
+		assert.NoError(t, err)
+		assert.Nil(t, result.Cmd)
+		verbose, _ := result.Flags.Bool("verbose")
+		assert.False(t, verbose.IsProvided(), "provided state should not leak between parses")
+		assert.Equal(t, flag.Value(".env"), result.Flags.Value("file"))
+	})
+
+	t.Run("should parse concurrently with the same application", func(t *testing.T) {
+		h := New(newApp())
+		var wg sync.WaitGroup
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				workers := strconv.Itoa(i)
+				result, err := h.Parse([]string{"app", "run", "--workers", workers})
+				assert.NoError(t, err)
+				assert.Equal(t, flag.Value(workers), result.CmdFlags.Value("workers"))
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	t.Run("should report help and version flags", func(t *testing.T) {
+		h := New(newApp())
+		result, err := h.Parse([]string{"app", "run", "--help"})
+		assert.NoError(t, err)
+		assert.True(t, result.Help)
+		assert.Equal(t, "run", result.Cmd.Name)
+
+		result, err = h.Parse([]string{"app", "--version"})
+		assert.NoError(t, err)
+		assert.True(t, result.Version)
+	})
+
+	t.Run("should return error for nil application", func(t *testing.T) {
+		_, err := New(nil).Parse([]string{"app"})
+		assert.EqualError(t, err, "error: application instance not found")
+	})
+}
+
+func TestHandler_Execute(t *testing.T) {
+	t.Run("should execute the command handler with the parsed values", func(t *testing.T) {
+		called := 0
+		ap := &app.App{
+			Commands: []app.Cmd{
+				{
+					Name:  "run",
+					Flags: []flag.Flag{flag.FlagString{Name: "name"}},
+					Handler: func(ctx *app.CmdContext) error {
+						called++
+						assert.Equal(t, flag.Value("a"), ctx.Flags.Value("name"))
+						assert.Equal(t, []string{"b"}, ctx.TailArgs)
+						return nil
+					},
+				},
+			},
+		}
+		h := New(ap)
+		result, err := h.Parse([]string{"app", "run", "--name", "a", "b"})
+		assert.NoError(t, err)
+		assert.NoError(t, h.Execute(result))
+		assert.NoError(t, h.Execute(result))
+		assert.Equal(t, 2, called)
+	})
+
+	t.Run("should return error for nil result", func(t *testing.T) {
+		assert.EqualError(t, New(&app.App{}).Execute(nil), "error: parse result not found")
+	})
+}
+
+func TestHandler_ParseErrors(t *testing.T) {
+	ap := &app.App{
+		Flags: []flag.Flag{
+			flag.FlagInt{Name: "num"},
+			flag.FlagString{Name: "file"},
+		},
+		Commands: []app.Cmd{
+			{Name: "run", Flags: []flag.Flag{flag.FlagBool{Name: "fast"}}},
+		},
+	}
+	tests := []struct {
+		name  string
+		opts  Options
+		vargs []string
+		want  helpers.ParseError
+	}{
+		{
+			name:  "should report unknown flags",
+			vargs: []string{"app", "run", "--slow"},
+			want: helpers.ParseError{
+				Kind: helpers.KindUnknownFlag, Index: 2, Flag: "slow", Command: "run", Token: "--slow",
+				Message: "error: unknown flag '--slow' argument",
+			},
+		},
+		{
+			name:  "should report missing values",
+			vargs: []string{"app", "--file", "--num", "1"},
+			want: helpers.ParseError{
+				Kind: helpers.KindMissingValue, Index: 1, Flag: "file", Token: "--file",
+				Message: "error: flag '--file' requires a value",
+			},
+		},
+		{
+			name:  "should report missing values at the end",
+			vargs: []string{"app", "--num"},
+			want: helpers.ParseError{
+				Kind: helpers.KindMissingValue, Index: 1, Flag: "num", Token: "--num",
+				Message: "error: flag '--num' requires a value",
+			},
+		},
+		{
+			name:  "should report invalid values",
+			vargs: []string{"app", "--num", "ten"},
+			want: helpers.ParseError{
+				Kind: helpers.KindInvalidValue, Index: 2, Flag: "num", Token: "ten",
+				Message: "error: invalid integer value for flag '--num'",
+			},
+		},
+		{
+			name:  "should report invalid arguments",
+			vargs: []string{"app", "--fïle"},
+			want: helpers.ParseError{
+				Kind: helpers.KindInvalidArgument, Index: 1, Flag: "fïle", Token: "--fïle",
+				Message: "error: flag 'fïle' contains invalid characters",
+			},
+		},
+		{
+			name:  "should report exceeded limits",
+			opts:  Options{MaxArgLen: 4},
+			vargs: []string{"app", "--file", "abcde"},
+			want: helpers.ParseError{
+				Kind: helpers.KindLimitExceeded, Index: 1, Token: "--file",
+				Message: "error: argument exceeds maximum length of 4 characters",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewWithOpts(ap, tt.opts).Parse(tt.vargs)
+			var perr *helpers.ParseError
+			if assert.True(t, errors.As(err, &perr), "error should be a ParseError") {
+				perr.Err = nil
+				assert.Equal(t, tt.want, *perr)
+			}
+		})
+	}
+}
+
+func TestHandler_Suggestions(t *testing.T) {
+	ap := &app.App{
+		Flags: []flag.Flag{
+			flag.FlagBool{Name: "verbose", Aliases: []string{"V"}},
+			flag.FlagString{Name: "file", Aliases: []string{"f"}},
+		},
+		Commands: []app.Cmd{
+			{Name: "info", Flags: []flag.Flag{flag.FlagInt{Name: "trace"}}},
+			{Name: "status"},
+		},
+	}
+	tests := []struct {
+		name            string
+		strict          bool
+		vargs           []string
+		wantErr         string
+		wantSuggestions []string
+	}{
+		{
+			name:            "should suggest a similar long flag",
+			vargs:           []string{"app", "--verbos"},
+			wantErr:         "error: unknown flag '--verbos' argument (did you mean '--verbose'?)",
+			wantSuggestions: []string{"--verbose"},
+		},
+		{
+			name:            "should suggest a long flag for a single dash key",
+			vargs:           []string{"app", "-fil"},
+			wantErr:         "error: unknown flag '-fil' argument (did you mean '--file'?)",
+			wantSuggestions: []string{"--file"},
+		},
+		{
+			name:            "should suggest built-in flags",
+			vargs:           []string{"app", "--hepl"},
+			wantErr:         "error: unknown flag '--hepl' argument (did you mean '--help'?)",
+			wantSuggestions: []string{"--help"},
+		},
+		{
+			name:            "should suggest command flags only within a command",
+			vargs:           []string{"app", "info", "--trase"},
+			wantErr:         "error: unknown flag '--trase' argument (did you mean '--trace'?)",
+			wantSuggestions: []string{"--trace"},
+		},
+		{
+			name:    "should not suggest distant flags",
+			vargs:   []string{"app", "--xyz"},
+			wantErr: "error: unknown flag '--xyz' argument",
+		},
+		{
+			name:            "should suggest a similar command",
+			strict:          true,
+			vargs:           []string{"app", "inof"},
+			wantErr:         "error: unknown command 'inof' (did you mean 'info'?)",
+			wantSuggestions: []string{"info"},
+		},
+		{
+			name:            "should suggest a similar command after global flags",
+			strict:          true,
+			vargs:           []string{"app", "-V", "--file", "a.env", "staus"},
+			wantErr:         "error: unknown command 'staus' (did you mean 'status'?)",
+			wantSuggestions: []string{"status"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sap := *ap
+			sap.StrictCommands = tt.strict
+			_, err := New(&sap).Parse(tt.vargs)
+			assert.EqualError(t, err, tt.wantErr)
+			var perr *helpers.ParseError
+			if assert.True(t, errors.As(err, &perr)) {
+				assert.Equal(t, tt.wantSuggestions, perr.Suggestions)
+			}
+		})
+	}
+
+	t.Run("should keep unrelated words as tail arguments", func(t *testing.T) {
+		result, err := New(ap).Parse([]string{"app", "something", "inof"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"something", "inof"}, result.TailArgs)
+	})
+
+	t.Run("should not suggest commands unless strict", func(t *testing.T) {
+		result, err := New(ap).Parse([]string{"app", "inof"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"inof"}, result.TailArgs)
+	})
+
+	t.Run("should not check flag values as commands", func(t *testing.T) {
+		result, err := New(ap).Parse([]string{"app", "--file", "inof"})
+		assert.NoError(t, err)
+		assert.Equal(t, flag.Value("inof"), result.Flags.Value("file"))
+	})
+}
+
+func TestHandler_UnknownCommands(t *testing.T) {
+	newApp := func() *app.App {
+		return &app.App{
+			Flags: []flag.Flag{flag.FlagBool{Name: "verbose"}},
+			Commands: []app.Cmd{
+				{Name: "info"},
+				{Name: "status"},
+			},
+		}
+	}
+
+	t.Run("should report unknown commands in strict mode", func(t *testing.T) {
+		ap := newApp()
+		ap.StrictCommands = true
+		_, err := New(ap).Parse([]string{"app", "--verbose", "deploy", "now"})
+		assert.EqualError(t, err, "error: unknown command 'deploy'")
+		assert.ErrorIs(t, err, helpers.ErrUnknownCommand)
+
+		var perr *helpers.ParseError
+		if assert.True(t, errors.As(err, &perr)) {
+			assert.Equal(t, 2, perr.Index)
+			assert.Equal(t, "deploy", perr.Command)
+		}
+	})
+
+	t.Run("should not report unknown commands when none are declared", func(t *testing.T) {
+		ap := &app.App{StrictCommands: true}
+		result, err := New(ap).Parse([]string{"app", "deploy"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"deploy"}, result.TailArgs)
+	})
+
+	t.Run("should call the not found handler with the word and remaining args", func(t *testing.T) {
+		called := false
+		ap := newApp()
+		ap.StrictCommands = true
+		ap.NotFound = func(ctx *app.AppContext, name string, args []string) error {
+			called = true
+			assert.Equal(t, "deploy", name)
+			assert.Equal(t, []string{"--force", "now"}, args)
+			v, _ := ctx.Flags().Bool("verbose")
+			assert.True(t, v.IsProvided())
+			return nil
+		}
+		ap.Handler = func(ctx *app.AppContext) error {
+			assert.Fail(t, "app handler should not be called")
+			return nil
+		}
+
+		assert.NoError(t, New(ap).Run([]string{"app", "--verbose", "deploy", "--force", "now"}))
+		assert.True(t, called)
+	})
+
+	t.Run("should prefer declared commands over the not found handler", func(t *testing.T) {
+		ap := newApp()
+		ap.NotFound = func(ctx *app.AppContext, name string, args []string) error {
+			return fmt.Errorf("not found")
+		}
+		result, err := New(ap).Parse([]string{"app", "info"})
+		assert.NoError(t, err)
+		assert.Equal(t, "info", result.Cmd.Name)
+		assert.Empty(t, result.NotFound)
+	})
+}
+
+func TestHandler_DefaultCommand(t *testing.T) {
+	newApp := func() *app.App {
+		return &app.App{
+			Name:           "app",
+			DefaultCommand: "serve",
+			Flags:          []flag.Flag{flag.FlagBool{Name: "verbose"}},
+			Commands: []app.Cmd{
+				{Name: "info"},
+				{
+					Name:  "serve",
+					Flags: []flag.Flag{flag.FlagInt{Name: "port", Aliases: []string{"p"}, Value: 8080}},
+				},
+			},
+		}
+	}
+
+	t.Run("should run the default command when no command is provided", func(t *testing.T) {
+		called := false
+		ap := newApp()
+		ap.Commands[1].Handler = func(ctx *app.CmdContext) error {
+			called = true
+			port, err := ctx.Flags.Int("port")
+			assert.NoError(t, err)
+			v, _ := port.Value()
+			assert.Equal(t, 8080, v)
+			return nil
+		}
+		ap.Handler = func(ctx *app.AppContext) error {
+			assert.Fail(t, "app handler should not be called")
+			return nil
+		}
+
+		assert.NoError(t, New(ap).Run([]string{"app", "--verbose"}))
+		assert.True(t, called)
+	})
+
+	t.Run("should parse the default command flags without the command word", func(t *testing.T) {
+		result, err := New(newApp()).Parse([]string{"app", "--verbose", "-p", "3000", "public"})
+		assert.NoError(t, err)
+		assert.Equal(t, "serve", result.Cmd.Name)
+		assert.Equal(t, []string{"public"}, result.TailArgs)
+		port, _ := result.CmdFlags.Int("port")
+		v, _ := port.Value()
+		assert.Equal(t, 3000, v)
+		verbose, _ := result.Flags.Bool("verbose")
+		assert.True(t, verbose.IsProvided())
+	})
+
+	t.Run("should route positional arguments to the default command", func(t *testing.T) {
+		result, err := New(newApp()).Parse([]string{"app", "public", "assets"})
+		assert.NoError(t, err)
+		assert.Equal(t, "serve", result.Cmd.Name)
+		assert.Equal(t, []string{"public", "assets"}, result.TailArgs)
+	})
+
+	t.Run("should prefer an explicit command", func(t *testing.T) {
+		result, err := New(newApp()).Parse([]string{"app", "info"})
+		assert.NoError(t, err)
+		assert.Equal(t, "info", result.Cmd.Name)
+	})
+
+	t.Run("should keep the application help and version", func(t *testing.T) {
+		result, err := New(newApp()).Parse([]string{"app", "--help"})
+		assert.NoError(t, err)
+		assert.Nil(t, result.Cmd)
+		assert.True(t, result.Help)
+
+		result, err = New(newApp()).Parse([]string{"app", "-v"})
+		assert.NoError(t, err)
+		assert.Nil(t, result.Cmd)
+		assert.True(t, result.Version)
+	})
+
+	t.Run("should report an undeclared default command", func(t *testing.T) {
+		ap := newApp()
+		ap.DefaultCommand = "run"
+		_, err := New(ap).Parse([]string{"app"})
+		assert.EqualError(t, err, "error: default command 'run' is not declared")
+		assert.ErrorIs(t, err, helpers.ErrInvalidDeclaration)
+	})
+}
+
+func TestHandler_ParseModes(t *testing.T) {
+	ap := &app.App{
+		Name: "app",
+		Flags: []flag.Flag{
+			flag.FlagBool{Name: "verbose"},
+			flag.FlagString{Name: "file", Aliases: []string{"f"}},
+		},
+		Commands: []app.Cmd{
+			{
+				Name:  "exec",
+				Flags: []flag.Flag{flag.FlagBool{Name: "quiet"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		mode        ParseMode
+		args        []string
+		wantCmd     string
+		wantVerbose bool
+		wantQuiet   bool
+		wantFile    string
+		wantTail    []string
+	}{
+		{
+			name:     "default mode should stop at the first positional argument",
+			mode:     ParseModeDefault,
+			args:     []string{"app", "a", "--verbose", "--", "b"},
+			wantTail: []string{"a", "--verbose", "b"},
+		},
+		{
+			name:        "default mode should consume a bool value",
+			mode:        ParseModeDefault,
+			args:        []string{"app", "--verbose", "true", "a"},
+			wantVerbose: true,
+			wantTail:    []string{"a"},
+		},
+		{
+			name:        "gnu mode should parse flags anywhere before the terminator",
+			mode:        ParseModeGNU,
+			args:        []string{"app", "a", "--verbose", "b", "-f", ".env", "c", "--", "--file", "d"},
+			wantVerbose: true,
+			wantFile:    ".env",
+			wantTail:    []string{"a", "b", "c", "--file", "d"},
+		},
+		{
+			name:        "gnu mode should not consume a bool value",
+			mode:        ParseModeGNU,
+			args:        []string{"app", "--verbose", "true"},
+			wantVerbose: true,
+			wantTail:    []string{"true"},
+		},
+		{
+			name:      "gnu mode should parse command flags after positional arguments",
+			mode:      ParseModeGNU,
+			args:      []string{"app", "exec", "a", "--quiet", "exec"},
+			wantCmd:   "exec",
+			wantQuiet: true,
+			wantTail:  []string{"a", "exec"},
+		},
+		{
+			name:     "posix mode should pass through arguments after the first positional argument",
+			mode:     ParseModePOSIX,
+			args:     []string{"app", "-f", ".env", "node", "--verbose", "--", "x"},
+			wantFile: ".env",
+			wantTail: []string{"node", "--verbose", "--", "x"},
+		},
+		{
+			name:      "posix mode should parse command flags",
+			mode:      ParseModePOSIX,
+			args:      []string{"app", "exec", "--quiet", "node", "--quiet"},
+			wantCmd:   "exec",
+			wantQuiet: true,
+			wantTail:  []string{"node", "--quiet"},
+		},
+		{
+			name:        "pass-through mode should pass through arguments after the command",
+			mode:        ParseModePassThrough,
+			args:        []string{"app", "--verbose", "exec", "--quiet", "node", "--", "--help"},
+			wantCmd:     "exec",
+			wantVerbose: true,
+			wantTail:    []string{"--quiet", "node", "--", "--help"},
+		},
+		{
+			name:     "pass-through mode should pass through arguments after the first positional argument",
+			mode:     ParseModePassThrough,
+			args:     []string{"app", "-f", ".env", "node", "--inspect"},
+			wantFile: ".env",
+			wantTail: []string{"node", "--inspect"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewWithOpts(ap, Options{ParseMode: tt.mode}).Parse(tt.args)
+			assert.NoError(t, err)
+
+			verbose, _ := result.Flags.Bool("verbose")
+			v, _ := verbose.Value()
+			assert.Equal(t, tt.wantVerbose, v)
+
+			file, _ := result.Flags.String("file")
+			assert.Equal(t, tt.wantFile, file.Value())
+
+			if tt.wantCmd == "" {
+				assert.Nil(t, result.Cmd)
+			} else if assert.NotNil(t, result.Cmd) {
+				assert.Equal(t, tt.wantCmd, result.Cmd.Name)
+				quiet, _ := result.CmdFlags.Bool("quiet")
+				q, _ := quiet.Value()
+				assert.Equal(t, tt.wantQuiet, q)
+			}
+			assert.Equal(t, tt.wantTail, result.TailArgs)
+		})
+	}
+}
+
+func TestHandler_Abbreviations(t *testing.T) {
+	ap := &app.App{
+		Name: "app",
+		Flags: []flag.Flag{
+			flag.FlagBool{Name: "verbose"},
+			flag.FlagBool{Name: "verbatim"},
+			flag.FlagString{Name: "output", Aliases: []string{"o"}},
+		},
+		Commands: []app.Cmd{
+			{Name: "info", Flags: []flag.Flag{flag.FlagInt{Name: "depth"}}},
+		},
+	}
+
+	t.Run("should resolve unambiguous prefixes", func(t *testing.T) {
+		result, err := NewWithOpts(ap, Options{AllowAbbreviations: true}).
+			Parse([]string{"app", "--verbo", "--out", "file", "info", "--dep", "2"})
+		assert.NoError(t, err)
+
+		verbose, _ := result.Flags.Bool("verbose")
+		assert.True(t, verbose.IsProvided())
+		assert.True(t, verbose.IsProvidedAbbreviated())
+
+		output, _ := result.Flags.String("output")
+		assert.Equal(t, "file", output.Value())
+		assert.True(t, output.IsProvidedAbbreviated())
+
+		depth, _ := result.CmdFlags.Int("depth")
+		v, _ := depth.Value()
+		assert.Equal(t, 2, v)
+	})
+
+	t.Run("should not mark exact names as abbreviated", func(t *testing.T) {
+		result, err := NewWithOpts(ap, Options{AllowAbbreviations: true}).Parse([]string{"app", "--verbose"})
+		assert.NoError(t, err)
+		verbose, _ := result.Flags.Bool("verbose")
+		assert.True(t, verbose.IsProvided())
+		assert.False(t, verbose.IsProvidedAbbreviated())
+	})
+
+	t.Run("should resolve the built-in flags", func(t *testing.T) {
+		result, err := NewWithOpts(ap, Options{AllowAbbreviations: true}).Parse([]string{"app", "--he"})
+		assert.NoError(t, err)
+		assert.True(t, result.Help)
+	})
+
+	t.Run("should report ambiguous prefixes with their candidates", func(t *testing.T) {
+		_, err := NewWithOpts(ap, Options{AllowAbbreviations: true}).Parse([]string{"app", "--verb"})
+		assert.EqualError(t, err, "error: ambiguous flag '--verb' argument (could be '--verbose' or '--verbatim')")
+		assert.ErrorIs(t, err, helpers.ErrAmbiguousFlag)
+
+		var perr *helpers.ParseError
+		if assert.True(t, errors.As(err, &perr)) {
+			assert.Equal(t, 1, perr.Index)
+			assert.Equal(t, []string{"--verbose", "--verbatim"}, perr.Suggestions)
+		}
+
+		_, err = NewWithOpts(ap, Options{AllowAbbreviations: true}).Parse([]string{"app", "--ver"})
+		assert.EqualError(t, err, "error: ambiguous flag '--ver' argument (could be '--version', '--verbose' or '--verbatim')")
+	})
+
+	t.Run("should not resolve prefixes unless enabled", func(t *testing.T) {
+		_, err := New(ap).Parse([]string{"app", "--verbo"})
+		assert.ErrorIs(t, err, helpers.ErrUnknownFlag)
+	})
+}
+
+func TestHandler_SingleDashLongFlags(t *testing.T) {
+	ap := &app.App{
+		Name: "app",
+		Flags: []flag.Flag{
+			flag.FlagBool{Name: "verbose"},
+			flag.FlagString{Name: "file", Aliases: []string{"f"}},
+			flag.FlagBool{Name: "n"},
+		},
+	}
+	opts := Options{SingleDashLongFlags: true}
+
+	t.Run("should match single-dash keys against long names", func(t *testing.T) {
+		result, err := NewWithOpts(ap, opts).Parse([]string{"app", "-verbose", "-file", ".env", "a"})
+		assert.NoError(t, err)
+
+		verbose, _ := result.Flags.Bool("verbose")
+		assert.True(t, verbose.IsProvidedLong())
+
+		file, _ := result.Flags.String("file")
+		assert.Equal(t, ".env", file.Value())
+		assert.True(t, file.IsProvidedLong())
+		assert.Equal(t, []string{"a"}, result.TailArgs)
+	})
+
+	t.Run("should keep matching aliases", func(t *testing.T) {
+		result, err := NewWithOpts(ap, opts).Parse([]string{"app", "-f", ".env", "-n"})
+		assert.NoError(t, err)
+
+		file, _ := result.Flags.String("file")
+		assert.True(t, file.IsProvidedShort())
+
+		n, _ := result.Flags.Bool("n")
+		assert.True(t, n.IsProvidedLong())
+	})
+
+	t.Run("should match the built-in flags", func(t *testing.T) {
+		result, err := NewWithOpts(ap, opts).Parse([]string{"app", "-help"})
+		assert.NoError(t, err)
+		assert.True(t, result.Help)
+	})
+
+	t.Run("should report unknown single-dash keys", func(t *testing.T) {
+		_, err := NewWithOpts(ap, opts).Parse([]string{"app", "-verbos"})
+		assert.EqualError(t, err, "error: unknown flag '-verbos' argument (did you mean '--verbose'?)")
+	})
+
+	t.Run("should treat single-dash keys as aliases unless enabled", func(t *testing.T) {
+		result, err := New(ap).Parse([]string{"app", "-verbose"})
+		assert.NoError(t, err)
+		verbose, _ := result.Flags.Bool("verbose")
+		assert.True(t, verbose.IsProvidedShort())
+	})
+}
+
+func TestHandler_ResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "args.txt")
+	if err := os.WriteFile(path, []byte("--verbose\n# paths\n'a b.txt' c.txt\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ap := &app.App{
+		Name:  "app",
+		Flags: []flag.Flag{flag.FlagBool{Name: "verbose"}},
+	}
+
+	t.Run("should expand response files", func(t *testing.T) {
+		result, err := NewWithOpts(ap, Options{ResponseFiles: true}).Parse([]string{"app", "@" + path, "d.txt"})
+		assert.NoError(t, err)
+		verbose, _ := result.Flags.Bool("verbose")
+		assert.True(t, verbose.IsProvided())
+		assert.Equal(t, []string{"a b.txt", "c.txt", "d.txt"}, result.TailArgs)
+	})
+
+	t.Run("should apply the arguments limits after the expansion", func(t *testing.T) {
+		_, err := NewWithOpts(ap, Options{ResponseFiles: true, MaxArgsCount: 3}).Parse([]string{"app", "@" + path})
+		assert.EqualError(t, err, "error: number of arguments exceeds the limit of 3")
+		assert.ErrorIs(t, err, helpers.ErrLimitExceeded)
+	})
+
+	t.Run("should not expand response files unless enabled", func(t *testing.T) {
+		result, err := New(ap).Parse([]string{"app", "@" + path})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"@" + path}, result.TailArgs)
+	})
+}
+
+func TestHandler_LookupEnv(t *testing.T) {
+	t.Parallel()
+
+	newApp := func() *app.App {
+		return &app.App{
+			Name:       "tool",
+			OptsEnvVar: "TOOL_OPTS",
+			Flags: []flag.Flag{
+				flag.FlagString{Name: "host", EnvVar: "TOOL_HOST"},
+				flag.FlagInt{Name: "port", EnvVar: "TOOL_PORT"},
+				flag.FlagBool{Name: "verbose"},
+			},
+			Commands: []app.Cmd{
+				{Name: "info", Flags: []flag.Flag{flag.FlagBool{Name: "trace", EnvVar: "TOOL_TRACE"}}},
+			},
+		}
+	}
+
+	t.Run("should look up the environment via the application", func(t *testing.T) {
+		t.Parallel()
+		ap := newApp()
+		ap.LookupEnv = flag.LookupEnvMap(map[string]string{
+			"TOOL_HOST":  "example.com",
+			"TOOL_PORT":  "8080",
+			"TOOL_OPTS":  "--verbose",
+			"TOOL_TRACE": "true",
+		})
+		result, err := New(ap).Parse([]string{"tool", "info"})
+		assert.NoError(t, err)
+
+		host, _ := result.Flags.String("host")
+		assert.Equal(t, "example.com", host.Value())
+		port, _ := result.Flags.Int("port")
+		v, _ := port.Value()
+		assert.Equal(t, 8080, v)
+		verbose, _ := result.Flags.Bool("verbose")
+		assert.True(t, verbose.IsProvided())
+		trace, _ := result.CmdFlags.Bool("trace")
+		b, _ := trace.Value()
+		assert.True(t, b)
+	})
+
+	t.Run("should prefer the handler options lookup", func(t *testing.T) {
+		t.Parallel()
+		ap := newApp()
+		ap.LookupEnv = flag.LookupEnvMap(map[string]string{"TOOL_HOST": "app"})
+		opts := Options{LookupEnv: flag.LookupEnvMap(map[string]string{"TOOL_HOST": "options"})}
+		result, err := NewWithOpts(ap, opts).Parse([]string{"tool"})
+		assert.NoError(t, err)
+
+		host, _ := result.Flags.String("host")
+		assert.Equal(t, "options", host.Value())
+	})
+
+	t.Run("should hide the process environment", func(t *testing.T) {
+		t.Parallel()
+		ap := newApp()
+		ap.Flags = append(ap.Flags, flag.FlagString{Name: "path", EnvVar: "PATH"})
+		ap.LookupEnv = flag.LookupEnvMap(nil)
+		result, err := New(ap).Parse([]string{"tool"})
+		assert.NoError(t, err)
+
+		path, _ := result.Flags.String("path")
+		assert.Equal(t, "", path.Value())
+	})
+}
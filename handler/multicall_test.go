@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+)
+
+// newMultiCallApp creates a multi-call application with the given output streams.
+func newMultiCallApp(out, errOut *bytes.Buffer) *app.App {
+	return &app.App{
+		Name:      "box",
+		MultiCall: true,
+		Writer:    out,
+		ErrWriter: errOut,
+		Commands: []app.Cmd{
+			{
+				Name:    "list",
+				Aliases: []string{"ls"},
+				Summary: "List entries",
+				Flags:   []flag.Flag{flag.FlagBool{Name: "all", Aliases: []string{"a"}}},
+			},
+			{Name: "copy", Aliases: []string{"cp"}},
+		},
+	}
+}
+
+func TestHandler_MultiCall(t *testing.T) {
+	tests := []struct {
+		name         string
+		disabled     bool
+		vargs        []string
+		wantCmd      string
+		wantProgram  string
+		wantTailArgs []string
+		wantAll      bool
+	}{
+		{
+			name:         "should select the command via the program name",
+			vargs:        []string{"/usr/local/bin/list", "-a", "dir"},
+			wantCmd:      "list",
+			wantProgram:  "list",
+			wantTailArgs: []string{"dir"},
+			wantAll:      true,
+		},
+		{
+			name:         "should select the command via a program name alias",
+			vargs:        []string{"ls", "dir"},
+			wantCmd:      "list",
+			wantProgram:  "ls",
+			wantTailArgs: []string{"dir"},
+		},
+		{
+			name:         "should fall back to command words for the main program name",
+			vargs:        []string{"./box", "cp", "a", "b"},
+			wantCmd:      "copy",
+			wantProgram:  "box",
+			wantTailArgs: []string{"a", "b"},
+		},
+		{
+			name:         "should ignore the program name when disabled",
+			disabled:     true,
+			vargs:        []string{"ls", "dir"},
+			wantProgram:  "box",
+			wantTailArgs: []string{"dir"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ap := newMultiCallApp(nil, nil)
+			ap.MultiCall = !tt.disabled
+			result, err := New(ap).Parse(tt.vargs)
+			assert.NoError(t, err)
+
+			if tt.wantCmd == "" {
+				assert.Nil(t, result.Cmd)
+			} else if assert.NotNil(t, result.Cmd) {
+				assert.Equal(t, tt.wantCmd, result.Cmd.Name)
+				all, _ := result.CmdFlags.Bool("all")
+				assert.Equal(t, tt.wantAll, all != nil && all.IsProvided())
+			}
+			assert.Equal(t, tt.wantProgram, result.ProgramName)
+			assert.Equal(t, tt.wantTailArgs, result.TailArgs)
+		})
+	}
+}
+
+func TestHandler_MultiCallOutput(t *testing.T) {
+	tests := []struct {
+		name       string
+		vargs      []string
+		wantCode   int
+		wantOut    []string
+		wantErrOut string
+	}{
+		{
+			name:  "should print command help using the invoked program name",
+			vargs: []string{"ls", "--help"},
+			wantOut: []string{
+				"USAGE:\n   ls [OPTIONS]\n",
+				"Run 'ls --help' for more information about this command",
+			},
+		},
+		{
+			name:  "should print application help using the invoked program name",
+			vargs: []string{"mybox", "--help"},
+			wantOut: []string{
+				"USAGE:\n   mybox [OPTIONS] COMMAND\n",
+				"   list, ls   List entries\n",
+			},
+		},
+		{
+			name:    "should print command help via a command word",
+			vargs:   []string{"mybox", "list", "--help"},
+			wantOut: []string{"USAGE:\n   mybox list [OPTIONS]\n"},
+		},
+		{
+			name:       "should print errors using the invoked program name",
+			vargs:      []string{"ls", "--unknown"},
+			wantCode:   ExitCodeUsage,
+			wantErrOut: "Run 'ls --help' for more information\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := New(newMultiCallApp(&out, &errOut)).Main(tt.vargs)
+			assert.Equal(t, tt.wantCode, code)
+			for _, want := range tt.wantOut {
+				assert.Contains(t, out.String(), want)
+			}
+			assert.Contains(t, errOut.String(), tt.wantErrOut)
+		})
+	}
+}
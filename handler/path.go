@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+// resolvePaths expands and checks the path flag values of the application and a given command (if any).
+func resolvePaths(ap *app.App, cmd *app.Cmd, lookup flag.LookupEnvFunc) error {
+	if err := resolvePathFlags(ap.Flags, lookup); err != nil {
+		return err
+	}
+	if cmd != nil {
+		if err := resolvePathFlags(cmd.Flags, lookup); err != nil {
+			var perr *helpers.ParseError
+			if errors.As(err, &perr) {
+				perr.Command = cmd.Name
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// resolvePathFlags expands and checks the values of a list of path flags.
+func resolvePathFlags(flags []flag.Flag, lookup flag.LookupEnvFunc) error {
+	for i, fl := range flags {
+		f, ok := fl.(flag.FlagPath)
+		if !ok {
+			continue
+		}
+		value := f.FlagValue.ToString()
+		path, err := helpers.ExpandPath(value, f.Absolute, lookup)
+		if err != nil {
+			return &helpers.ParseError{
+				Kind:    helpers.KindInvalidValue,
+				Index:   -1,
+				Flag:    f.Name,
+				Token:   value,
+				Message: fmt.Sprintf("error: unable to resolve path '%s' of flag '--%s'", value, f.Name),
+				Err:     err,
+			}
+		}
+		if err := helpers.CheckPath(f, path); err != nil {
+			var perr *helpers.ParseError
+			if errors.As(err, &perr) && f.FlagSource.Kind == flag.SourceArg {
+				perr.Index = f.FlagSource.Index
+			}
+			return err
+		}
+		f.FlagValue = flag.Value(path)
+		flags[i] = f
+	}
+	return nil
+}
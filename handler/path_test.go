@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+func TestHandler_PathFlags(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "input.txt")
+	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	missing := dir + "/missing.txt"
+	missingCache := filepath.Join(dir, "missing", "cache")
+
+	tests := []struct {
+		name         string
+		env          map[string]string
+		vargs        []string
+		wantErr      string
+		wantErrFlag  string
+		wantErrToken string
+		wantErrIndex int
+		wantCommand  string
+		wantInput    string
+		wantCache    string
+	}{
+		{
+			name:      "should expand and check the path values",
+			env:       map[string]string{"DATA": dir, "HOME": dir},
+			vargs:     []string{"tool", "-i", "~/input.txt"},
+			wantInput: file,
+			wantCache: filepath.Join(dir, "cache"),
+		},
+		{
+			name:         "should report the resolved path of invalid values",
+			env:          map[string]string{"DATA": dir, "HOME": dir},
+			vargs:        []string{"tool", "--input", "~/missing.txt"},
+			wantErr:      "error: path '" + missing + "' of flag '--input' does not exist",
+			wantErrFlag:  "input",
+			wantErrToken: missing,
+			wantErrIndex: 1,
+		},
+		{
+			name:         "should check the environment variable values",
+			env:          map[string]string{"TOOL_CACHE": missingCache},
+			vargs:        []string{"tool"},
+			wantErr:      "error: parent directory '" + filepath.Dir(missingCache) + "' of path '" + missingCache + "' of flag '--cache' does not exist",
+			wantErrFlag:  "cache",
+			wantErrToken: missingCache,
+			wantErrIndex: -1,
+		},
+		{
+			name:         "should check the command path values",
+			env:          map[string]string{"DATA": dir},
+			vargs:        []string{"tool", "build", "--out", file},
+			wantErr:      "error: path '" + file + "' of flag '--out' is not a directory",
+			wantErrFlag:  "out",
+			wantErrToken: file,
+			wantErrIndex: 2,
+			wantCommand:  "build",
+		},
+		{
+			name:      "should not check the path values on help",
+			vargs:     []string{"tool", "--input", "missing.txt", "--help"},
+			wantInput: "missing.txt",
+			wantCache: "$DATA/cache",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ap := &app.App{
+				Name: "tool",
+				Flags: []flag.Flag{
+					flag.FlagPath{Name: "input", Aliases: []string{"i"}, MustExist: true, MustBeFile: true},
+					flag.FlagPath{Name: "cache", Value: "$DATA/cache", EnvVar: "TOOL_CACHE", Absolute: true, ParentMustExist: true},
+				},
+				Commands: []app.Cmd{
+					{Name: "build", Flags: []flag.Flag{flag.FlagPath{Name: "out", MustBeDir: true}}},
+				},
+			}
+			result, err := NewWithOpts(ap, Options{LookupEnv: flag.LookupEnvMap(tt.env)}).Parse(tt.vargs)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, helpers.ErrInvalidValue)
+				var perr *helpers.ParseError
+				if assert.ErrorAs(t, err, &perr) {
+					assert.Equal(t, tt.wantErrFlag, perr.Flag)
+					assert.Equal(t, tt.wantErrToken, perr.Token)
+					assert.Equal(t, tt.wantErrIndex, perr.Index)
+					assert.Equal(t, tt.wantCommand, perr.Command)
+				}
+				return
+			}
+			assert.NoError(t, err)
+
+			input, _ := result.Flags.Path("input")
+			assert.Equal(t, tt.wantInput, filepath.Clean(input.Value()))
+			cache, _ := result.Flags.Path("cache")
+			assert.Equal(t, tt.wantCache, cache.Value())
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/joseluisq/cline/flag"
+)
+
+// runPlugin executes the external plugin command of a given parse result with its tail arguments
+// using the application streams.
+// The plugin environment includes the parent application details via `CLINE_*` variables.
+// A non-zero plugin exit status is returned as an `ExitError` carrying the same code.
+func runPlugin(ctx context.Context, result *ParseResult) error {
+	ap := result.App
+	plugin := result.Plugin
+	cmd := exec.CommandContext(ctx, plugin.Path, result.TailArgs...)
+	cmd.Stdin = ap.Stdin()
+	cmd.Stdout = ap.Stdout()
+	cmd.Stderr = ap.Stderr()
+
+	bin, _ := os.Executable()
+	cmd.Env = append(
+		pluginEnv(result.LookupEnv, result.Env),
+		"CLINE_APP_NAME="+ap.Name,
+		"CLINE_APP_VERSION="+ap.Version,
+		"CLINE_APP_PATH="+bin,
+		"CLINE_PLUGIN_NAME="+plugin.Name,
+	)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			return Exit(nil, exitErr.ExitCode())
+		}
+		return err
+	}
+	return nil
+}
+
+// pluginEnv returns the plugin environment made of the process and the dotenv files variable names
+// whose values are resolved via a given lookup function (the process environment if nil).
+func pluginEnv(lookup flag.LookupEnvFunc, vars map[string]string) []string {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	var env []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name == "" || seen[name] {
+			return
+		}
+		seen[name] = true
+		if val, ok := lookup(name); ok {
+			env = append(env, name+"="+val)
+		}
+	}
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		add(name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		add(name)
+	}
+	return env
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+)
+
+func TestHandler_Plugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins are not supported on Windows")
+	}
+
+	dir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"echo \"$CLINE_APP_NAME $CLINE_APP_VERSION $CLINE_PLUGIN_NAME $*\"\n" +
+		"read line\n" +
+		"echo \"stdin: $line\"\n" +
+		"echo oops >&2\n" +
+		"exit ${PLUGIN_EXIT_CODE:-0}\n"
+	if err := os.WriteFile(filepath.Join(dir, "tool-hello"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	envFile := filepath.Join(dir, "plugin.env")
+	if err := os.WriteFile(envFile, []byte("PLUGIN_EXIT_CODE=3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		vargs      []string
+		opts       Options
+		pluginDirs []string
+		envFiles   []string
+		disabled   bool
+		wantCode   int
+		wantOut    string
+		wantHelp   string
+		wantErrOut string
+	}{
+		{
+			name:       "should dispatch unknown commands to plugins",
+			vargs:      []string{"tool", "--verbose", "hello", "--name", "x"},
+			pluginDirs: []string{dir},
+			wantCode:   ExitCodeOK,
+			wantOut:    "tool 1.2.3 hello --name x\nstdin: input\n",
+			wantErrOut: "oops\n",
+		},
+		{
+			name:       "should return the plugin exit code",
+			vargs:      []string{"tool", "hello"},
+			pluginDirs: []string{dir},
+			envFiles:   []string{envFile},
+			wantCode:   3,
+			wantOut:    "tool 1.2.3 hello \nstdin: input\n",
+			wantErrOut: "oops\n",
+		},
+		{
+			name:       "should find plugins via the resolved PATH",
+			vargs:      []string{"tool", "hello"},
+			opts:       Options{LookupEnv: flag.LookupEnvMap(map[string]string{"PATH": dir})},
+			wantCode:   ExitCodeOK,
+			wantOut:    "tool 1.2.3 hello \nstdin: input\n",
+			wantErrOut: "oops\n",
+		},
+		{
+			name:     "should list plugins found via the resolved PATH in the help",
+			vargs:    []string{"tool", "--help"},
+			opts:     Options{LookupEnv: flag.LookupEnvMap(map[string]string{"PATH": dir})},
+			wantCode: ExitCodeOK,
+			wantHelp: "PLUGINS:\n   hello",
+		},
+		{
+			name:       "should not dispatch when plugins are disabled",
+			vargs:      []string{"tool", "hello"},
+			pluginDirs: []string{dir},
+			disabled:   true,
+			wantCode:   ExitCodeUsage,
+			wantErrOut: "error: unknown command 'hello'\nRun 'tool --help' for more information\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			ap := &app.App{
+				Name:           "tool",
+				Version:        "1.2.3",
+				Plugins:        !tt.disabled,
+				StrictCommands: tt.disabled,
+				PluginDirs:     tt.pluginDirs,
+				EnvFiles:       tt.envFiles,
+				Writer:         &out,
+				ErrWriter:      &errOut,
+				Reader:         bytes.NewBufferString("input\n"),
+				Flags:          []flag.Flag{flag.FlagBool{Name: "verbose"}},
+				Commands:       []app.Cmd{{Name: "info"}},
+				Handler: func(ctx *app.AppContext) error {
+					assert.Fail(t, "app handler should not be called")
+					return nil
+				},
+			}
+			code := NewWithOpts(ap, tt.opts).Main(tt.vargs)
+			assert.Equal(t, tt.wantCode, code)
+			if tt.wantHelp != "" {
+				assert.Contains(t, out.String(), tt.wantHelp)
+			} else {
+				assert.Equal(t, tt.wantOut, out.String())
+			}
+			assert.Equal(t, tt.wantErrOut, errOut.String())
+		})
+	}
+}
+
+func Test_pluginEnv(t *testing.T) {
+	t.Setenv("CLINE_TEST_PLUGIN_VAR", "process")
+
+	tests := []struct {
+		name   string
+		lookup flag.LookupEnvFunc
+		vars   map[string]string
+		want   []string
+	}{
+		{
+			name: "should resolve the process variables via the lookup function",
+			lookup: flag.LookupEnvMap(map[string]string{
+				"CLINE_TEST_PLUGIN_VAR": "resolved",
+			}),
+			want: []string{"CLINE_TEST_PLUGIN_VAR=resolved"},
+		},
+		{
+			name: "should include the dotenv files variables",
+			lookup: flag.LookupEnvMap(map[string]string{
+				"CLINE_TEST_PLUGIN_VAR": "resolved",
+				"TOOL_B":                "b",
+				"TOOL_A":                "a",
+			}),
+			vars: map[string]string{"TOOL_B": "b", "TOOL_A": "a"},
+			want: []string{"CLINE_TEST_PLUGIN_VAR=resolved", "TOOL_A=a", "TOOL_B=b"},
+		},
+		{
+			name:   "should skip the variables not found",
+			lookup: flag.LookupEnvMap(map[string]string{}),
+			vars:   map[string]string{"TOOL_A": "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, pluginEnv(tt.lookup, tt.vars))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+// readSecret reads a secret value from a given file path or from a given reader if the path is `-`.
+// Trailing newlines are trimmed and the read buffer is zeroed afterwards.
+func readSecret(stdin io.Reader, path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	defer clear(data)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// applySecretEnv reads the values of the application and selected command (if any) secret flags from the files
+// of their `<ENV>_FILE` environment variables unless they were already set via the command line
+// or their environment variables.
+func applySecretEnv(ap *app.App, cmd *app.Cmd, lookup flag.LookupEnvFunc) error {
+	if err := applySecretEnvFlags(ap.Flags, lookup, ap.Stdin()); err != nil {
+		return err
+	}
+	if cmd == nil {
+		return nil
+	}
+	if err := applySecretEnvFlags(cmd.Flags, lookup, ap.Stdin()); err != nil {
+		var perr *helpers.ParseError
+		if errors.As(err, &perr) {
+			perr.Command = cmd.Name
+		}
+		return err
+	}
+	return nil
+}
+
+// applySecretEnvFlags reads the values of a list of secret flags from their `<ENV>_FILE` environment variables files.
+func applySecretEnvFlags(flags []flag.Flag, lookup flag.LookupEnvFunc, stdin io.Reader) error {
+	for i, fl := range flags {
+		f, ok := fl.(flag.FlagString)
+		if !ok || !f.Secret || f.FlagSource.Kind != flag.SourceDefault {
+			continue
+		}
+		for _, name := range append([]string{f.EnvVar}, f.EnvVars...) {
+			if name == "" {
+				continue
+			}
+			path, ok := lookup(name + "_FILE")
+			if !ok {
+				continue
+			}
+			value, err := readSecret(stdin, path)
+			if err != nil {
+				return &helpers.ParseError{
+					Kind:    helpers.KindInvalidValue,
+					Index:   -1,
+					Flag:    f.Name,
+					Token:   path,
+					Message: fmt.Sprintf("error: unable to read secret file of environment variable '%s_FILE' for flag '--%s': %s", name, f.Name, err),
+					Err:     err,
+				}
+			}
+			f.FlagValue = flag.Value(value)
+			f.FlagSource = flag.Source{Kind: flag.SourceEnv, EnvVar: name + "_FILE", File: path}
+			flags[i] = f
+			break
+		}
+	}
+	return nil
+}
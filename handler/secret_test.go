@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+	"github.com/joseluisq/cline/print"
+)
+
+func TestHandler_SecretFlags(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "token")
+	if err := os.WriteFile(path, []byte("file-secret\r\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		name            string
+		stdin           string
+		env             map[string]string
+		vargs           []string
+		wantErr         string
+		wantErrIs       error
+		wantCommand     string
+		wantToken       string
+		wantTokenSource *flag.Source
+		wantKey         string
+		wantTailArgs    []string
+		wantStdin       string
+	}{
+		{
+			name:            "should read the secret value from a file flag",
+			vargs:           []string{"tool", "--token-file", path, "--host", "example.com"},
+			wantToken:       "file-secret",
+			wantTokenSource: &flag.Source{Kind: flag.SourceArg, Index: 1, File: path},
+		},
+		{
+			name:         "should read the secret value from stdin",
+			stdin:        "stdin-secret\n",
+			vargs:        []string{"tool", "--token", "-", "file.txt"},
+			wantToken:    "stdin-secret",
+			wantTailArgs: []string{"file.txt"},
+		},
+		{
+			name:      "should read the secret value from stdin via a file flag",
+			stdin:     "stdin-secret",
+			vargs:     []string{"tool", "--token-file", "-"},
+			wantToken: "stdin-secret",
+		},
+		{
+			name:      "should keep plain secret values",
+			vargs:     []string{"tool", "--token", "plain"},
+			wantToken: "plain",
+		},
+		{
+			name:            "should read the secret values from the file environment variables",
+			env:             map[string]string{"TOKEN_FILE": path, "PUSH_KEY_FILE": path},
+			vargs:           []string{"tool", "push"},
+			wantToken:       "file-secret",
+			wantTokenSource: &flag.Source{Kind: flag.SourceEnv, EnvVar: "TOKEN_FILE", File: path},
+			wantKey:         "file-secret",
+		},
+		{
+			name:      "should prefer the environment variable over its file variant",
+			env:       map[string]string{"TOKEN": "env-secret", "TOKEN_FILE": path},
+			vargs:     []string{"tool"},
+			wantToken: "env-secret",
+		},
+		{
+			name:      "should prefer the command line over the file environment variable",
+			env:       map[string]string{"TOKEN_FILE": path},
+			vargs:     []string{"tool", "--token", "arg-secret"},
+			wantToken: "arg-secret",
+		},
+		{
+			name:      "should not read the file environment variables of other commands",
+			stdin:     "stdin-secret",
+			env:       map[string]string{"PUSH_KEY_FILE": "-", "PULL_KEY_FILE": missing},
+			vargs:     []string{"tool"},
+			wantStdin: "stdin-secret",
+		},
+		{
+			name:      "should not read the file environment variables for help",
+			stdin:     "stdin-secret",
+			env:       map[string]string{"TOKEN_FILE": "-", "PUSH_KEY_FILE": missing},
+			vargs:     []string{"tool", "push", "--help"},
+			wantStdin: "stdin-secret",
+		},
+		{
+			name:      "should report unreadable secret files",
+			vargs:     []string{"tool", "--token-file", missing},
+			wantErr:   "error: unable to read secret value of flag '--token': ",
+			wantErrIs: os.ErrNotExist,
+		},
+		{
+			name:        "should report unreadable secret files of the environment variables",
+			env:         map[string]string{"PUSH_KEY_FILE": missing},
+			vargs:       []string{"tool", "push"},
+			wantErr:     "error: unable to read secret file of environment variable 'PUSH_KEY_FILE' for flag '--key': ",
+			wantErrIs:   os.ErrNotExist,
+			wantCommand: "push",
+		},
+		{
+			name:      "should not accept the file variant of non-secret flags",
+			vargs:     []string{"tool", "--host-file", path},
+			wantErr:   "error: unknown flag '--host-file' argument",
+			wantErrIs: helpers.ErrUnknownFlag,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdin := strings.NewReader(tt.stdin)
+			ap := &app.App{
+				Name:   "tool",
+				Reader: stdin,
+				Flags: []flag.Flag{
+					flag.FlagString{Name: "token", EnvVar: "TOKEN", Secret: true},
+					flag.FlagString{Name: "host"},
+				},
+				Commands: []app.Cmd{
+					{Name: "push", Flags: []flag.Flag{flag.FlagString{Name: "key", EnvVar: "PUSH_KEY", Secret: true}}},
+					{Name: "pull", Flags: []flag.Flag{flag.FlagString{Name: "key", EnvVar: "PULL_KEY", Secret: true}}},
+				},
+			}
+
+			result, err := NewWithOpts(ap, Options{LookupEnv: flag.LookupEnvMap(tt.env)}).Parse(tt.vargs)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				var perr *helpers.ParseError
+				if assert.ErrorAs(t, err, &perr) {
+					assert.Equal(t, tt.wantCommand, perr.Command)
+				}
+				return
+			}
+			assert.NoError(t, err)
+
+			token, _ := result.Flags.String("token")
+			assert.Equal(t, tt.wantToken, token.Value())
+			if tt.wantToken != "" {
+				assert.Equal(t, flag.SecretMask, token.String())
+				assert.NotContains(t, fmt.Sprintf("%v %+v", result.Flags.GetProvided(), result.Flags.GetProvided()), tt.wantToken)
+			}
+			if tt.wantTokenSource != nil {
+				assert.Equal(t, *tt.wantTokenSource, token.Source())
+			}
+			if result.CmdFlags != nil {
+				key, _ := result.CmdFlags.String("key")
+				assert.Equal(t, tt.wantKey, key.Value())
+			}
+			assert.ElementsMatch(t, tt.wantTailArgs, result.TailArgs)
+			rest, _ := io.ReadAll(stdin)
+			assert.Equal(t, tt.wantStdin, string(rest))
+		})
+	}
+}
+
+func TestHandler_SecretFlagsOutput(t *testing.T) {
+	ap := &app.App{
+		Name:  "tool",
+		Flags: []flag.Flag{flag.FlagString{Name: "token", Secret: true}},
+	}
+	result, err := New(ap).Parse([]string{"tool", "--token", "plain"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		print func() error
+		want  string
+	}{
+		{
+			name:  "should mask the secret values in the print config output",
+			print: func() error { return print.PrintConfig(result.App, nil, print.FormatJSON) },
+			want:  `"value": "` + flag.SecretMask + `"`,
+		},
+		{
+			name:  "should mask the secret values in the help output",
+			print: func() error { return print.PrintHelp(result.App, nil) },
+			want:  "[default: " + flag.SecretMask + "]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			result.App.Writer = &out
+			assert.NoError(t, tt.print())
+			assert.Contains(t, out.String(), tt.want)
+			assert.NotContains(t, out.String(), "plain")
+		})
+	}
+}
+
+func Test_readSecret(t *testing.T) {
+	value, err := readSecret(strings.NewReader("secret\n"), "-")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", value)
+}
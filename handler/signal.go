@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const defaultGracePeriod = 5 * time.Second
+
+// invoke calls the given application or command handler function.
+// When signal handling is enabled, it cancels the handler context on SIGINT or SIGTERM,
+// waits for the handler to return within the grace period and exits with the conventional code.
+func (h *Handler) invoke(ctx context.Context, fn func(context.Context) error) error {
+	if !h.opts.HandleSignals {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sigs := make(chan os.Signal, 2)
+	notify := h.notify
+	if notify == nil {
+		notify = signal.Notify
+	}
+	notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case sig := <-sigs:
+		cancel()
+
+		grace := h.opts.GracePeriod
+		if grace <= 0 {
+			grace = defaultGracePeriod
+		}
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+
+		// Wait for the handler to finish unless the grace period expires
+		// or a second signal forces the exit.
+		select {
+		case <-done:
+		case <-timer.C:
+		case <-sigs:
+		}
+
+		h.exitWith(signalExitCode(sig))
+		return fmt.Errorf("error: interrupted by signal %s", sig)
+	}
+}
+
+// exitWith terminates the program with the given status code.
+func (h *Handler) exitWith(code int) {
+	if h.exit != nil {
+		h.exit(code)
+		return
+	}
+	os.Exit(code)
+}
+
+// signalExitCode returns the conventional exit code (128 + signal number) for a given signal.
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return 1
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+)
+
+func TestHandler_RunContext(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("should propagate the context to the app handler", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey{}, "app")
+		ap := &app.App{
+			Handler: func(c *app.AppContext) error {
+				assert.Equal(t, "app", c.Context().Value(ctxKey{}))
+				return nil
+			},
+		}
+		assert.NoError(t, New(ap).RunContext(ctx, []string{"app"}))
+	})
+
+	t.Run("should propagate the context to the command handler", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), ctxKey{}, "cmd")
+		ap := &app.App{
+			Commands: []app.Cmd{
+				{
+					Name: "run",
+					Handler: func(c *app.CmdContext) error {
+						assert.Equal(t, "cmd", c.Context().Value(ctxKey{}))
+						assert.Equal(t, "cmd", c.AppContext.Context().Value(ctxKey{}))
+						return nil
+					},
+				},
+			},
+		}
+		assert.NoError(t, New(ap).RunContext(ctx, []string{"app", "run"}))
+	})
+
+	t.Run("should use a background context on Run", func(t *testing.T) {
+		ap := &app.App{
+			Handler: func(c *app.AppContext) error {
+				assert.NotNil(t, c.Context())
+				assert.NoError(t, c.Context().Err())
+				return nil
+			},
+		}
+		assert.NoError(t, New(ap).Run([]string{"app"}))
+	})
+}
+
+func TestHandler_invoke(t *testing.T) {
+	tests := []struct {
+		name     string
+		sig      os.Signal
+		wantCode int
+	}{
+		{
+			name:     "should cancel the context and exit with 130 on SIGINT",
+			sig:      syscall.SIGINT,
+			wantCode: 130,
+		},
+		{
+			name:     "should cancel the context and exit with 143 on SIGTERM",
+			sig:      syscall.SIGTERM,
+			wantCode: 143,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sigs := make(chan chan<- os.Signal, 1)
+			gotCode := -1
+			h := NewWithOpts(&app.App{}, Options{HandleSignals: true, GracePeriod: time.Second})
+			h.exit = func(code int) { gotCode = code }
+			h.notify = func(c chan<- os.Signal, _ ...os.Signal) { sigs <- c }
+
+			cancelled := false
+			go func() { (<-sigs) <- tt.sig }()
+			err := h.invoke(context.Background(), func(ctx context.Context) error {
+				<-ctx.Done()
+				cancelled = true
+				return ctx.Err()
+			})
+
+			assert.Error(t, err)
+			assert.True(t, cancelled, "handler context should be cancelled")
+			assert.Equal(t, tt.wantCode, gotCode)
+		})
+	}
+
+	t.Run("should exit after the grace period if the handler does not return", func(t *testing.T) {
+		sigs := make(chan chan<- os.Signal, 1)
+		gotCode := -1
+		h := NewWithOpts(&app.App{}, Options{HandleSignals: true, GracePeriod: 10 * time.Millisecond})
+		h.exit = func(code int) { gotCode = code }
+		h.notify = func(c chan<- os.Signal, _ ...os.Signal) { sigs <- c }
+
+		block := make(chan struct{})
+		defer close(block)
+		go func() { (<-sigs) <- syscall.SIGINT }()
+		err := h.invoke(context.Background(), func(ctx context.Context) error {
+			<-block
+			return nil
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 130, gotCode)
+	})
+
+	t.Run("should return the handler error when no signal is received", func(t *testing.T) {
+		h := NewWithOpts(&app.App{}, Options{HandleSignals: true})
+		h.exit = func(code int) { assert.Fail(t, "exit should not be called") }
+		h.notify = func(c chan<- os.Signal, _ ...os.Signal) {}
+
+		wantErr := errors.New("handler error")
+		err := h.invoke(context.Background(), func(ctx context.Context) error {
+			return wantErr
+		})
+		assert.Equal(t, wantErr, err)
+	})
+}
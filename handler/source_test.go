@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+)
+
+// newSourcesApp creates an application with flags of every source loading a given config file.
+func newSourcesApp(t *testing.T, path string, out *bytes.Buffer) *app.App {
+	return &app.App{
+		Name:        "tool",
+		Writer:      out,
+		ConfigPaths: []string{path},
+		PrintConfig: true,
+		Flags: []flag.Flag{
+			flag.FlagString{Name: "host", EnvVar: "TOOL_HOST"},
+			flag.FlagInt{Name: "port", Value: 80},
+			flag.FlagBool{Name: "verbose"},
+			flag.FlagString{Name: "name", Value: "x"},
+		},
+		Handler: func(ctx *app.AppContext) error {
+			assert.Fail(t, "app handler should not be called")
+			return nil
+		},
+	}
+}
+
+func TestHandler_Sources(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tool.json")
+	if err := os.WriteFile(path, []byte(`{"port": 3000}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lookup := flag.LookupEnvMap(map[string]string{"TOOL_HOST": "example.com"})
+
+	tests := []struct {
+		name  string
+		vargs []string
+		want  map[string]flag.Source
+	}{
+		{
+			name:  "should track the flag value sources",
+			vargs: []string{"tool", "a", "--verbose"},
+			want: map[string]flag.Source{
+				"host": {Kind: flag.SourceEnv, EnvVar: "TOOL_HOST"},
+				"port": {Kind: flag.SourceConfig, File: path},
+				"name": {Kind: flag.SourceDefault},
+			},
+		},
+		{
+			name:  "should track the argument index of provided flags",
+			vargs: []string{"tool", "--verbose", "--host", "localhost"},
+			want: map[string]flag.Source{
+				"verbose": {Kind: flag.SourceArg, Index: 1},
+				"host":    {Kind: flag.SourceArg, Index: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewWithOpts(newSourcesApp(t, path, nil), Options{LookupEnv: lookup}).Parse(tt.vargs)
+			assert.NoError(t, err)
+
+			for name, want := range tt.want {
+				var got flag.Source
+				switch name {
+				case "port":
+					v, _ := result.Flags.Int(name)
+					got = v.Source()
+				case "verbose":
+					v, _ := result.Flags.Bool(name)
+					got = v.Source()
+				default:
+					v, _ := result.Flags.String(name)
+					got = v.Source()
+				}
+				assert.Equal(t, want, got, name)
+			}
+		})
+	}
+}
+
+func TestHandler_PrintConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tool.json")
+	if err := os.WriteFile(path, []byte(`{"port": 3000}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lookup := flag.LookupEnvMap(map[string]string{"TOOL_HOST": "example.com"})
+
+	tests := []struct {
+		name    string
+		vargs   []string
+		opts    Options
+		want    []string
+		wantErr string
+	}{
+		{
+			name:  "should print the effective values",
+			vargs: []string{"tool", "--print-config", "--name", "y"},
+			want: []string{
+				"--host      example.com   env TOOL_HOST\n",
+				"--port      3000          config " + path + "\n",
+				"--name      y             arg 2\n",
+			},
+		},
+		{
+			name:  "should print the effective values as json",
+			vargs: []string{"tool", "--print-config", "json"},
+			want:  []string{`"source": "config"`},
+		},
+		{
+			name:  "should list the print config flag in help",
+			vargs: []string{"tool", "--help"},
+			want:  []string{"--print-config   Prints the effective flag values and their sources (table or json)"},
+		},
+		{
+			name:  "should resolve print config flag abbreviations",
+			vargs: []string{"tool", "--print"},
+			opts:  Options{AllowAbbreviations: true},
+			want:  []string{"--port      3000          config " + path + "\n"},
+		},
+		{
+			name:    "should suggest the print config flag",
+			vargs:   []string{"tool", "--print-conf"},
+			wantErr: "error: unknown flag '--print-conf' argument (did you mean '--print-config'?)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			opts := tt.opts
+			opts.LookupEnv = lookup
+			err := NewWithOpts(newSourcesApp(t, path, &out), opts).Run(tt.vargs)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			for _, want := range tt.want {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+// flagSuggestions returns the flags similar to an unknown flag key (including leading dashes).
+// Long flag keys are compared with long names only, alias keys with both aliases and long names.
+func flagSuggestions(key string, isAlias bool, flags []flag.Flag, isCmd bool, printConfig bool) []string {
+	names := []string{"help"}
+	aliases := []string{"h"}
+	if !isCmd {
+		names = append(names, "version")
+		aliases = append(aliases, "v")
+	}
+	if printConfig {
+		names = append(names, "print-config")
+	}
+	for _, fl := range flags {
+		name, flagAliases := helpers.FlagNames(fl)
+		names = append(names, name)
+		aliases = append(aliases, flagAliases...)
+	}
+
+	var suggestions []string
+	if isAlias {
+		for _, s := range helpers.Suggest(key, aliases) {
+			suggestions = append(suggestions, "-"+s)
+		}
+	}
+	for _, s := range helpers.Suggest(key, names) {
+		suggestions = append(suggestions, "--"+s)
+	}
+	return suggestions
+}
+
+// commandSuggestions returns the command names similar to an unknown command name.
+func commandSuggestions(name string, cmds []app.Cmd) []string {
+	names := make([]string, 0, len(cmds))
+	for _, c := range cmds {
+		names = append(names, c.Name)
+		names = append(names, c.Aliases...)
+	}
+	return helpers.Suggest(name, names)
+}
+
+// withSuggestions appends the given suggestions to a parse error message.
+func withSuggestions(err *helpers.ParseError, suggestions []string) *helpers.ParseError {
+	if len(suggestions) == 0 {
+		return err
+	}
+	err.Suggestions = suggestions
+	err.Message = fmt.Sprintf("%s (did you mean %s?)", err.Message, quoteList(suggestions))
+	return err
+}
+
+// quoteList returns a human readable list of quoted items (e.g. `'a', 'b' or 'c'`).
+func quoteList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = "'" + s + "'"
+	}
+	if n := len(quoted); n > 1 {
+		return strings.Join(quoted[:n-1], ", ") + " or " + quoted[n-1]
+	}
+	return strings.Join(quoted, "")
+}
+
+// abbreviationCandidates returns the long flag names (including the built-in help, version and print config flags)
+// starting with a given flag key. An exact name match is returned alone.
+func abbreviationCandidates(key string, isCmd bool, printConfig bool, flags ...[]flag.Flag) []string {
+	names := []string{"help"}
+	if !isCmd {
+		names = append(names, "version")
+	}
+	if printConfig {
+		names = append(names, "print-config")
+	}
+	for _, fls := range flags {
+		for _, fl := range fls {
+			name, _ := helpers.FlagNames(fl)
+			names = append(names, name)
+		}
+	}
+	return helpers.MatchPrefix(key, names)
+}
@@ -0,0 +1,160 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// SplitArgs splits a given string into arguments like a POSIX shell does but without any expansion.
+// It supports single and double quotes, backslash escapes and comments starting with `#` at the beginning of a word.
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var word strings.Builder
+	var inWord = false
+
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == '\'':
+			// Single quotes preserve every character literally
+			end := -1
+			for j := i + 1; j < len(rs); j++ {
+				if rs[j] == '\'' {
+					end = j
+					break
+				}
+			}
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			word.WriteString(string(rs[i+1 : end]))
+			inWord = true
+			i = end
+		case r == '"':
+			// Double quotes only allow escaping double quotes, backslashes, dollars and backquotes
+			end := -1
+			for j := i + 1; j < len(rs); j++ {
+				if rs[j] == '\\' && j+1 < len(rs) && strings.ContainsRune("\"\\$`", rs[j+1]) {
+					word.WriteRune(rs[j+1])
+					j++
+					continue
+				}
+				if rs[j] == '"' {
+					end = j
+					break
+				}
+				word.WriteRune(rs[j])
+			}
+			if end < 0 {
+				return nil, errors.New("unterminated double quote")
+			}
+			inWord = true
+			i = end
+		case r == '\\':
+			if i+1 < len(rs) {
+				i++
+				r = rs[i]
+			}
+			word.WriteRune(r)
+			inWord = true
+		case r == '#' && !inWord:
+			// Skip the comment until the end of the line
+			for i+1 < len(rs) && rs[i+1] != '\n' {
+				i++
+			}
+		case unicode.IsSpace(r):
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
+
+// ExpandResponseFiles replaces every `@path` argument (except the program name `argv[0]`)
+// by the arguments read from the given response file until the `--` terminator.
+// Response files are split line by line via `SplitArgs` and they can refer to other response files
+// up to a given nesting depth. Errors point at the response file name and line involved.
+func ExpandResponseFiles(vArgs []string, maxDepth int) ([]string, error) {
+	if len(vArgs) == 0 {
+		return vArgs, nil
+	}
+
+	expanded := make([]string, 0, len(vArgs))
+	expanded = append(expanded, vArgs[0])
+	for i := 1; i < len(vArgs); i++ {
+		arg := vArgs[i]
+		if arg == "--" {
+			expanded = append(expanded, vArgs[i:]...)
+			break
+		}
+		if !isResponseFile(arg) {
+			expanded = append(expanded, arg)
+			continue
+		}
+		args, err := readResponseFile(arg[1:], "", 1, maxDepth)
+		if err != nil {
+			return nil, &ParseError{
+				Kind:    KindInvalidArgument,
+				Index:   i,
+				Token:   arg,
+				Message: err.Error(),
+				Err:     err,
+			}
+		}
+		expanded = append(expanded, args...)
+	}
+	return expanded, nil
+}
+
+// isResponseFile checks if a given argument refers to a response file (e.g. `@args.txt`).
+func isResponseFile(arg string) bool {
+	return len(arg) > 1 && arg[0] == '@'
+}
+
+// readResponseFile reads the arguments of a given response file referenced at a given origin (`file:line`).
+func readResponseFile(path string, origin string, depth int, maxDepth int) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if origin != "" {
+			return nil, fmt.Errorf("error: %s: unable to read response file '%s': %w", origin, path, err)
+		}
+		return nil, fmt.Errorf("error: unable to read response file '%s': %w", path, err)
+	}
+
+	var args []string
+	for n, line := range strings.Split(string(data), "\n") {
+		words, err := SplitArgs(strings.TrimSuffix(line, "\r"))
+		if err != nil {
+			return nil, fmt.Errorf("error: %s:%d: %w", path, n+1, err)
+		}
+		for _, w := range words {
+			if !isResponseFile(w) {
+				args = append(args, w)
+				continue
+			}
+			at := fmt.Sprintf("%s:%d", path, n+1)
+			if depth >= maxDepth {
+				return nil, fmt.Errorf("error: %s: response files nesting exceeds the limit of %d", at, maxDepth)
+			}
+			nested, err := readResponseFile(w[1:], at, depth+1, maxDepth)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, nested...)
+		}
+	}
+	return args, nil
+}
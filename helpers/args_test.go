@@ -0,0 +1,96 @@
+package helpers_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/helpers"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr string
+	}{
+		{name: "should return no arguments for blanks", input: " \t ", want: nil},
+		{name: "should split on whitespaces", input: "  a b\tc\nd ", want: []string{"a", "b", "c", "d"}},
+		{name: "should preserve single quoted text", input: `'a b' 'c"d\e'`, want: []string{"a b", `c"d\e`}},
+		{name: "should unescape double quoted text", input: `"a \"b\" \\ \$x \n"`, want: []string{`a "b" \ $x \n`}},
+		{name: "should join quoted and unquoted text", input: `--name="John Doe"x ''`, want: []string{"--name=John Doex", ""}},
+		{name: "should unescape unquoted text", input: `a\ b \#c`, want: []string{"a b", "#c"}},
+		{name: "should skip comments", input: "a # b c\nd e#f", want: []string{"a", "d", "e#f"}},
+		{name: "should report unterminated single quotes", input: "a 'b", wantErr: "unterminated single quote"},
+		{name: "should report unterminated double quotes", input: `a "b\"`, wantErr: "unterminated double quote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := helpers.SplitArgs(tt.input)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, args)
+		})
+	}
+}
+
+func TestExpandResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+	// Paths are single quoted within response files since backslashes are escape characters
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	nested := write("nested.txt", "c 'd e'\r\n")
+	args := write("args.txt", "# files\n--file a.txt\n@'"+nested+"'\n\"f g\" # trailing\n")
+	invalid := write("invalid.txt", "a\nb 'c\n")
+	missing := write("missing.txt", "a\n@'"+filepath.Join(dir, "none.txt")+"'\n")
+	loop := filepath.Join(dir, "loop.txt")
+	write("loop.txt", "x @'"+loop+"'\n")
+
+	t.Run("should expand response files recursively", func(t *testing.T) {
+		expanded, err := helpers.ExpandResponseFiles([]string{"@app", "-v", "@" + args, "@", "z"}, 8)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"@app", "-v", "--file", "a.txt", "c", "d e", "f g", "@", "z"}, expanded)
+	})
+
+	t.Run("should not expand response files after the terminator", func(t *testing.T) {
+		expanded, err := helpers.ExpandResponseFiles([]string{"app", "--", "@" + args}, 8)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"app", "--", "@" + args}, expanded)
+	})
+
+	t.Run("should report syntax errors with the file and line", func(t *testing.T) {
+		_, err := helpers.ExpandResponseFiles([]string{"app", "a", "@" + invalid}, 8)
+		assert.EqualError(t, err, "error: "+invalid+":2: unterminated single quote")
+
+		var perr *helpers.ParseError
+		if assert.True(t, errors.As(err, &perr)) {
+			assert.Equal(t, helpers.KindInvalidArgument, perr.Kind)
+			assert.Equal(t, 2, perr.Index)
+			assert.Equal(t, "@"+invalid, perr.Token)
+		}
+	})
+
+	t.Run("should report unreadable files with the referring file and line", func(t *testing.T) {
+		_, err := helpers.ExpandResponseFiles([]string{"app", "@" + missing}, 8)
+		assert.ErrorContains(t, err, "error: "+missing+":2: unable to read response file '"+filepath.Join(dir, "none.txt")+"'")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("should report nesting beyond the limit", func(t *testing.T) {
+		_, err := helpers.ExpandResponseFiles([]string{"app", "@" + loop}, 3)
+		assert.EqualError(t, err, "error: "+loop+":1: response files nesting exceeds the limit of 3")
+	})
+}
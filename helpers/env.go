@@ -0,0 +1,219 @@
+package helpers
+
+import (
+	"errors"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/joseluisq/cline/flag"
+)
+
+// ExpandEnv replaces the `$VAR`, `${VAR}`, `${VAR:-default}` (unset or empty) and `${VAR-default}` (unset)
+// references of a given string with the values of a given lookup function (the process environment if nil).
+// Default values are also expanded, `$$` is replaced by a single `$` and unset variables by empty strings.
+func ExpandEnv(s string, lookup flag.LookupEnvFunc) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		switch next := s[i+1]; {
+		case next == '$':
+			b.WriteByte('$')
+			i++
+		case next == '{':
+			end := closingBrace(s, i+2)
+			if end < 0 {
+				b.WriteString(s[i:])
+				return b.String()
+			}
+			b.WriteString(expandBraces(s[i+2:end], lookup))
+			i = end
+		case isEnvNameChar(next, true):
+			j := i + 1
+			for j < len(s) && isEnvNameChar(s[j], false) {
+				j++
+			}
+			v, _ := lookup(s[i+1 : j])
+			b.WriteString(v)
+			i = j - 1
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String()
+}
+
+// expandBraces expands the inner part of a `${...}` reference.
+func expandBraces(ref string, lookup flag.LookupEnvFunc) string {
+	name, def, hasDef := ref, "", false
+	onlyUnset := false
+	if i := strings.IndexAny(ref, ":-"); i >= 0 {
+		switch {
+		case strings.HasPrefix(ref[i:], ":-"):
+			name, def, hasDef = ref[:i], ref[i+2:], true
+		case ref[i] == '-':
+			name, def, hasDef, onlyUnset = ref[:i], ref[i+1:], true, true
+		}
+	}
+
+	v, ok := lookup(name)
+	if hasDef && (!ok || (!onlyUnset && v == "")) {
+		return ExpandEnv(def, lookup)
+	}
+	return v
+}
+
+// closingBrace returns the index of the brace closing a `${` reference starting at a given index or -1.
+func closingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isEnvNameChar checks if a given character is valid in an environment variable name.
+func isEnvNameChar(c byte, first bool) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+}
+
+// EnvVarName returns an environment variable name (e.g. `TOOL_LOG_LEVEL`) for a given prefix and name
+// by uppercasing them and replacing dashes with underscores. The prefix may end with an underscore.
+func EnvVarName(prefix string, name string) string {
+	name = strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	prefix = strings.TrimSuffix(strings.ToUpper(strings.ReplaceAll(prefix, "-", "_")), "_")
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+// WithEnvPrefix returns a copy of the given flags where the ones without environment variables
+// get a variable name derived from a given prefix and their names (e.g. `TOOL_TRACE`).
+// The flags are returned unchanged when the prefix is empty.
+func WithEnvPrefix(flags []flag.Flag, prefix string) []flag.Flag {
+	if prefix == "" || len(flags) == 0 {
+		return flags
+	}
+	vflags := make([]flag.Flag, len(flags))
+	for i, fl := range flags {
+		switch f := fl.(type) {
+		case flag.FlagBool:
+			if f.EnvVar == "" && len(f.EnvVars) == 0 {
+				f.EnvVar = EnvVarName(prefix, f.Name)
+			}
+			fl = f
+		case flag.FlagInt:
+			if f.EnvVar == "" && len(f.EnvVars) == 0 {
+				f.EnvVar = EnvVarName(prefix, f.Name)
+			}
+			fl = f
+		case flag.FlagString:
+			if f.EnvVar == "" && len(f.EnvVars) == 0 {
+				f.EnvVar = EnvVarName(prefix, f.Name)
+			}
+			fl = f
+		case flag.FlagStringSlice:
+			if f.EnvVar == "" && len(f.EnvVars) == 0 {
+				f.EnvVar = EnvVarName(prefix, f.Name)
+			}
+			fl = f
+		case flag.FlagPath:
+			if f.EnvVar == "" && len(f.EnvVars) == 0 {
+				f.EnvVar = EnvVarName(prefix, f.Name)
+			}
+			fl = f
+		}
+		vflags[i] = fl
+	}
+	return vflags
+}
+
+// EnvMode defines how invalid flag environment variable values are handled.
+type EnvMode int
+
+const (
+	// EnvModeIgnore silently ignores invalid values so the flags fall back to their default values.
+	EnvModeIgnore EnvMode = iota
+	// EnvModeWarn reports invalid values through a logger so the flags fall back to their default values.
+	EnvModeWarn
+	// EnvModeStrict reports invalid values as errors.
+	EnvModeStrict
+)
+
+// EnvOptions defines how the flag environment variables are resolved.
+type EnvOptions struct {
+	// An optional function to look up the environment variables. It defaults to the process environment.
+	Lookup flag.LookupEnvFunc
+	// How invalid environment variable values are handled. It defaults to `EnvModeIgnore`.
+	Mode EnvMode
+	// An optional logger for the `EnvModeWarn` warnings. It defaults to the standard error output.
+	Logger *log.Logger
+}
+
+// CheckFlagsEnv checks the environment variable values of a list of validated flags
+// not provided via the command line and handles the invalid ones according to given options.
+func CheckFlagsEnv(flags []flag.Flag, opts EnvOptions) error {
+	for _, v := range flags {
+		var err error
+		switch f := v.(type) {
+		case flag.FlagBool:
+			if !f.FlagProvided {
+				err = f.CheckEnv(opts.Lookup)
+			}
+		case flag.FlagInt:
+			if !f.FlagProvided {
+				err = f.CheckEnv(opts.Lookup)
+			}
+		}
+		if err2 := opts.checkEnv(err); err2 != nil {
+			return err2
+		}
+	}
+	return nil
+}
+
+// checkEnv handles a given flag environment variable error according to the options mode.
+func (o EnvOptions) checkEnv(err error) error {
+	var envErr *flag.EnvError
+	if !errors.As(err, &envErr) {
+		return nil
+	}
+	switch o.Mode {
+	case EnvModeStrict:
+		return &ParseError{
+			Kind:    KindInvalidValue,
+			Index:   -1,
+			Flag:    envErr.Flag,
+			Token:   envErr.Value,
+			Message: "error: " + envErr.Error(),
+			Err:     envErr,
+		}
+	case EnvModeWarn:
+		logger := o.Logger
+		if logger == nil {
+			logger = log.New(os.Stderr, "", 0)
+		}
+		logger.Printf("warning: %s, using the default value", envErr)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package helpers_test
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+func TestExpandEnv(t *testing.T) {
+	vars := map[string]string{"HOME": "/home/me", "EMPTY": "", "NAME": "cline"}
+	lookup := func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "no references", want: "no references"},
+		{input: "$HOME/bin", want: "/home/me/bin"},
+		{input: "${HOME}bin", want: "/home/mebin"},
+		{input: "$NAME_x ${NAME}_x", want: " cline_x"},
+		{input: "${UNSET:-def} ${EMPTY:-def} ${EMPTY-def} ${UNSET-def}", want: "def def  def"},
+		{input: "${UNSET:-$HOME/${NAME}}", want: "/home/me/cline"},
+		{input: "$$HOME costs $5 $", want: "$HOME costs $5 $"},
+		{input: "${HOME", want: "${HOME"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, helpers.ExpandEnv(tt.input, lookup))
+		})
+	}
+
+	t.Run("should use the process environment by default", func(t *testing.T) {
+		t.Setenv("CLINE_EXPAND_TEST", "ok")
+		assert.Equal(t, "ok", helpers.ExpandEnv("${CLINE_EXPAND_TEST}", nil))
+	})
+}
+
+func TestEnvVarName(t *testing.T) {
+	assert.Equal(t, "TOOL_LOG_LEVEL", helpers.EnvVarName("TOOL", "log-level"))
+	assert.Equal(t, "TOOL_INFO_TRACE", helpers.EnvVarName("TOOL_INFO_", "trace"))
+	assert.Equal(t, "MY_TOOL_TRACE", helpers.EnvVarName("my-tool", "trace"))
+	assert.Equal(t, "TRACE", helpers.EnvVarName("", "trace"))
+}
+
+func TestWithEnvPrefix(t *testing.T) {
+	flags := []flag.Flag{
+		flag.FlagBool{Name: "trace"},
+		flag.FlagInt{Name: "log-level", EnvVar: "LOG_LEVEL"},
+		flag.FlagString{Name: "name", EnvVars: []string{"NAME"}},
+		flag.FlagStringSlice{Name: "tags"},
+	}
+
+	vflags := helpers.WithEnvPrefix(flags, "TOOL")
+	assert.Equal(t, []flag.Flag{
+		flag.FlagBool{Name: "trace", EnvVar: "TOOL_TRACE"},
+		flag.FlagInt{Name: "log-level", EnvVar: "LOG_LEVEL"},
+		flag.FlagString{Name: "name", EnvVars: []string{"NAME"}},
+		flag.FlagStringSlice{Name: "tags", EnvVar: "TOOL_TAGS"},
+	}, vflags)
+	assert.Equal(t, flag.FlagBool{Name: "trace"}, flags[0])
+	assert.Equal(t, flags, helpers.WithEnvPrefix(flags, ""))
+}
+
+func TestValidateFlagsAndInitEnv(t *testing.T) {
+	lookup := flag.LookupEnvMap(map[string]string{"WORKERS": "ten"})
+	flags := []flag.Flag{flag.FlagInt{Name: "workers", Value: 4, EnvVar: "WORKERS"}}
+
+	tests := []struct {
+		name        string
+		commands    bool
+		mode        helpers.EnvMode
+		wantErr     string
+		wantCommand string
+		wantLog     string
+	}{
+		{
+			name: "should ignore invalid values by default",
+		},
+		{
+			name:    "should report invalid values as errors in strict mode",
+			mode:    helpers.EnvModeStrict,
+			wantErr: "error: invalid value 'ten' of environment variable 'WORKERS' for flag '--workers'",
+		},
+		{
+			name:        "should report invalid command flag values as errors in strict mode",
+			commands:    true,
+			mode:        helpers.EnvModeStrict,
+			wantErr:     "error: invalid value 'ten' of environment variable 'WORKERS' for flag '--workers'",
+			wantCommand: "run",
+		},
+		{
+			name:    "should report invalid values through the logger in warn mode",
+			mode:    helpers.EnvModeWarn,
+			wantLog: "warning: invalid value 'ten' of environment variable 'WORKERS' for flag '--workers', using the default value\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			opts := helpers.EnvOptions{Lookup: lookup, Mode: tt.mode, Logger: log.New(&out, "", 0)}
+
+			var vflags []flag.Flag
+			var err error
+			if tt.commands {
+				var cmds []app.Cmd
+				if cmds, err = helpers.ValidateCommandsEnv([]app.Cmd{{Name: "run", Flags: flags}}, opts); err == nil {
+					vflags = cmds[0].Flags
+				}
+			} else {
+				vflags, err = helpers.ValidateFlagsAndInitEnv(flags, opts)
+			}
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, helpers.ErrInvalidValue)
+				var perr *helpers.ParseError
+				if assert.ErrorAs(t, err, &perr) {
+					assert.Equal(t, "workers", perr.Flag)
+					assert.Equal(t, "ten", perr.Token)
+					assert.Equal(t, tt.wantCommand, perr.Command)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, flag.Value("4"), vflags[0].(flag.FlagInt).FlagValue)
+			assert.Equal(t, tt.wantLog, out.String())
+		})
+	}
+}
+
+func TestCheckFlagsEnv(t *testing.T) {
+	lookup := flag.LookupEnvMap(map[string]string{"WORKERS": "ten", "DRY": "maybe", "NAME": "x"})
+
+	tests := []struct {
+		name    string
+		flags   []flag.Flag
+		wantErr string
+	}{
+		{
+			name:    "should report invalid int values",
+			flags:   []flag.Flag{flag.FlagInt{Name: "workers", EnvVar: "WORKERS"}},
+			wantErr: "error: invalid value 'ten' of environment variable 'WORKERS' for flag '--workers'",
+		},
+		{
+			name:    "should report invalid bool values",
+			flags:   []flag.Flag{flag.FlagString{Name: "name", EnvVar: "NAME"}, flag.FlagBool{Name: "dry", EnvVar: "DRY"}},
+			wantErr: "error: invalid value 'maybe' of environment variable 'DRY' for flag '--dry'",
+		},
+		{
+			name:  "should skip the flags provided via the command line",
+			flags: []flag.Flag{flag.FlagInt{Name: "workers", EnvVar: "WORKERS", FlagProvided: true}},
+		},
+		{
+			name:  "should skip the flags without environment variables",
+			flags: []flag.Flag{flag.FlagInt{Name: "workers"}, flag.FlagBool{Name: "dry"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := helpers.CheckFlagsEnv(tt.flags, helpers.EnvOptions{Lookup: lookup, Mode: helpers.EnvModeStrict})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package helpers
+
+// ErrorKind defines the kind of a parsing or validation error.
+type ErrorKind int
+
+const (
+	// KindInvalidDeclaration reports an invalid application, command or flag declaration.
+	KindInvalidDeclaration ErrorKind = iota + 1
+	// KindInvalidArgument reports a malformed input argument.
+	KindInvalidArgument
+	// KindUnknownFlag reports an input flag which is not declared.
+	KindUnknownFlag
+	// KindMissingValue reports an input flag which requires a value but none was provided.
+	KindMissingValue
+	// KindInvalidValue reports an input flag value which cannot be converted into its flag type.
+	KindInvalidValue
+	// KindUnknownCommand reports an input command which is not declared.
+	KindUnknownCommand
+	// KindLimitExceeded reports an input which exceeds the arguments limits.
+	KindLimitExceeded
+	// KindAmbiguousFlag reports an input flag abbreviation which matches several flags.
+	KindAmbiguousFlag
+)
+
+// String returns the name of the error kind.
+func (k ErrorKind) String() string {
+	switch k {
+	case KindInvalidDeclaration:
+		return "invalid declaration"
+	case KindInvalidArgument:
+		return "invalid argument"
+	case KindUnknownFlag:
+		return "unknown flag"
+	case KindMissingValue:
+		return "missing value"
+	case KindInvalidValue:
+		return "invalid value"
+	case KindUnknownCommand:
+		return "unknown command"
+	case KindLimitExceeded:
+		return "limit exceeded"
+	case KindAmbiguousFlag:
+		return "ambiguous flag"
+	default:
+		return "unknown"
+	}
+}
+
+// Sentinel errors to check for a given error kind via `errors.Is`.
+var (
+	ErrInvalidDeclaration = &ParseError{Kind: KindInvalidDeclaration}
+	ErrInvalidArgument    = &ParseError{Kind: KindInvalidArgument}
+	ErrUnknownFlag        = &ParseError{Kind: KindUnknownFlag}
+	ErrMissingValue       = &ParseError{Kind: KindMissingValue}
+	ErrInvalidValue       = &ParseError{Kind: KindInvalidValue}
+	ErrUnknownCommand     = &ParseError{Kind: KindUnknownCommand}
+	ErrLimitExceeded      = &ParseError{Kind: KindLimitExceeded}
+	ErrAmbiguousFlag      = &ParseError{Kind: KindAmbiguousFlag}
+)
+
+// ParseError defines an error which occurred while validating declarations or parsing input arguments.
+type ParseError struct {
+	// The kind of the error.
+	Kind ErrorKind
+	// The input argument index (`argv`) involved or -1 if not related to an argument.
+	Index int
+	// The flag name involved (if any).
+	Flag string
+	// The command name involved (if any).
+	Command string
+	// The raw input argument or declaration token involved (if any).
+	Token string
+	// Similar flag or command names suggested for an unknown one (if any).
+	Suggestions []string
+	// The error message.
+	Message string
+	// An optional underlying error.
+	Err error
+}
+
+// Error returns the error message.
+func (e *ParseError) Error() string {
+	if e.Message == "" && e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is a `ParseError` of the same kind.
+func (e *ParseError) Is(target error) bool {
+	t, ok := target.(*ParseError)
+	return ok && t.Kind == e.Kind
+}
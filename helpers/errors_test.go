@@ -0,0 +1,67 @@
+package helpers_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+func TestParseError(t *testing.T) {
+	t.Run("should return the error message", func(t *testing.T) {
+		err := &helpers.ParseError{Kind: helpers.KindUnknownFlag, Message: "error: unknown flag '--x' argument"}
+		assert.EqualError(t, err, "error: unknown flag '--x' argument")
+	})
+
+	t.Run("should fall back to the underlying error message", func(t *testing.T) {
+		_, inner := strconv.Atoi("x")
+		err := &helpers.ParseError{Kind: helpers.KindInvalidValue, Err: inner}
+		assert.EqualError(t, err, inner.Error())
+		assert.ErrorIs(t, err, inner)
+	})
+
+	t.Run("should match sentinel errors by kind", func(t *testing.T) {
+		err := error(&helpers.ParseError{Kind: helpers.KindMissingValue, Flag: "file"})
+		assert.ErrorIs(t, err, helpers.ErrMissingValue)
+		assert.NotErrorIs(t, err, helpers.ErrUnknownFlag)
+	})
+
+	t.Run("should return the kind names", func(t *testing.T) {
+		assert.Equal(t, "invalid declaration", helpers.KindInvalidDeclaration.String())
+		assert.Equal(t, "invalid argument", helpers.KindInvalidArgument.String())
+		assert.Equal(t, "unknown flag", helpers.KindUnknownFlag.String())
+		assert.Equal(t, "missing value", helpers.KindMissingValue.String())
+		assert.Equal(t, "invalid value", helpers.KindInvalidValue.String())
+		assert.Equal(t, "unknown command", helpers.KindUnknownCommand.String())
+		assert.Equal(t, "limit exceeded", helpers.KindLimitExceeded.String())
+		assert.Equal(t, "ambiguous flag", helpers.KindAmbiguousFlag.String())
+		assert.Equal(t, "unknown", helpers.ErrorKind(0).String())
+	})
+}
+
+func TestParseError_Declarations(t *testing.T) {
+	t.Run("should return a declaration error for an invalid flag", func(t *testing.T) {
+		_, err := helpers.ValidateFlagsAndInit([]flag.Flag{flag.FlagBool{Name: ""}})
+		var perr *helpers.ParseError
+		assert.True(t, errors.As(err, &perr))
+		assert.Equal(t, helpers.KindInvalidDeclaration, perr.Kind)
+		assert.Equal(t, -1, perr.Index)
+	})
+
+	t.Run("should return a declaration error with the command involved", func(t *testing.T) {
+		_, err := helpers.ValidateCommands([]app.Cmd{
+			{Name: "run", Flags: []flag.Flag{flag.FlagString{Name: "fïle"}}},
+		})
+		var perr *helpers.ParseError
+		assert.True(t, errors.As(err, &perr))
+		assert.Equal(t, helpers.KindInvalidDeclaration, perr.Kind)
+		assert.Equal(t, "run", perr.Command)
+		assert.Equal(t, "fïle", perr.Flag)
+		assert.EqualError(t, err, "error: flag 'fïle' contains invalid characters")
+	})
+}
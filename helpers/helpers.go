@@ -2,6 +2,7 @@
 package helpers
 
 import (
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -12,6 +13,11 @@ import (
 
 // ValidateCommands checks if a list of commands and initialize them if they are valid.
 func ValidateCommands(commands []app.Cmd) (cmds []app.Cmd, err error) {
+	return ValidateCommandsEnv(commands, EnvOptions{})
+}
+
+// ValidateCommandsEnv is like ValidateCommands but it resolves the flag environment variables via given options.
+func ValidateCommandsEnv(commands []app.Cmd, opts EnvOptions) (cmds []app.Cmd, err error) {
 	for _, c := range commands {
 		name := strings.TrimSpace(c.Name)
 		if err2 := IsValidToken(name, "command"); err2 != nil {
@@ -19,11 +25,21 @@ func ValidateCommands(commands []app.Cmd) (cmds []app.Cmd, err error) {
 		}
 
 		if name == "" {
-			err = fmt.Errorf("error: command name cannot be empty")
+			err = declarationError("error: command name cannot be empty")
 			return
 		}
-		flags, errf := ValidateFlagsAndInit(c.Flags)
+		for _, alias := range c.Aliases {
+			if err2 := IsValidToken(alias, "command"); err2 != nil || alias == "" {
+				err = declarationError("error: command '%s' alias '%s' contains invalid characters", name, alias)
+				return
+			}
+		}
+		flags, errf := ValidateFlagsAndInitEnv(c.Flags, opts)
 		if errf != nil {
+			var perr *ParseError
+			if errors.As(errf, &perr) {
+				perr.Command = name
+			}
 			err = errf
 			return
 		}
@@ -33,64 +49,103 @@ func ValidateCommands(commands []app.Cmd) (cmds []app.Cmd, err error) {
 	return
 }
 
+// FindCommand finds a command by its name or one of its aliases.
+func FindCommand(name string, commands []app.Cmd) (index int, cmd *app.Cmd) {
+	for i := range commands {
+		if commands[i].Name == name || slices.Contains(commands[i].Aliases, name) {
+			return i, &commands[i]
+		}
+	}
+	return -1, nil
+}
+
 // ValidateFlagsAndInit checks a list of flags and initialize them if they are valid.
 func ValidateFlagsAndInit(flags []flag.Flag) (vflags []flag.Flag, err error) {
+	return ValidateFlagsAndInitEnv(flags, EnvOptions{})
+}
+
+// ValidateFlagsAndInitEnv is like ValidateFlagsAndInit but it resolves the flag environment variables via given options.
+// Invalid environment variable values are handled according to the options mode.
+func ValidateFlagsAndInitEnv(flags []flag.Flag, opts EnvOptions) (vflags []flag.Flag, err error) {
+	lookup := opts.Lookup
 	for _, v := range flags {
 		if v == nil {
-			err = fmt.Errorf("error: flag list contains a nil value")
+			err = declarationError("error: flag list contains a nil value")
 			return
 		}
 		switch f := v.(type) {
 		case flag.FlagBool:
 			name := strings.ToLower(strings.TrimSpace(f.Name))
 			if name == "" {
-				err = fmt.Errorf("error: bool flag name cannot be empty")
+				err = declarationError("error: bool flag name cannot be empty")
 				return
 			}
 			if err2 := IsValidToken(name, "flag"); err2 != nil {
 				return vflags, err2
 			}
-			f.Init()
+			if err2 := opts.checkEnv(f.CheckEnv(lookup)); err2 != nil {
+				return vflags, err2
+			}
+			f.InitEnv(lookup)
 			vflags = append(vflags, f)
 
 		case flag.FlagInt:
 			name := strings.ToLower(strings.TrimSpace(f.Name))
 			if name == "" {
-				err = fmt.Errorf("error: int flag name cannot be empty")
+				err = declarationError("error: int flag name cannot be empty")
 				return
 			}
 			if err2 := IsValidToken(name, "flag"); err2 != nil {
 				return vflags, err2
 			}
-			f.Init()
+			if err2 := opts.checkEnv(f.CheckEnv(lookup)); err2 != nil {
+				return vflags, err2
+			}
+			f.InitEnv(lookup)
 			vflags = append(vflags, f)
 
 		case flag.FlagString:
 			name := strings.ToLower(strings.TrimSpace(f.Name))
 			if name == "" {
-				err = fmt.Errorf("error: string flag name cannot be empty")
+				err = declarationError("error: string flag name cannot be empty")
 				return
 			}
 			if err2 := IsValidToken(name, "flag"); err2 != nil {
 				return vflags, err2
 			}
-			f.Init()
+			f.InitEnv(lookup)
 			vflags = append(vflags, f)
 
 		case flag.FlagStringSlice:
 			name := strings.ToLower(strings.TrimSpace(f.Name))
 			if name == "" {
-				err = fmt.Errorf("error: string slice flag name cannot be empty")
+				err = declarationError("error: string slice flag name cannot be empty")
+				return
+			}
+			if err2 := IsValidToken(name, "flag"); err2 != nil {
+				return vflags, err2
+			}
+			f.InitEnv(lookup)
+			vflags = append(vflags, f)
+
+		case flag.FlagPath:
+			name := strings.ToLower(strings.TrimSpace(f.Name))
+			if name == "" {
+				err = declarationError("error: path flag name cannot be empty")
 				return
 			}
 			if err2 := IsValidToken(name, "flag"); err2 != nil {
 				return vflags, err2
 			}
-			f.Init()
+			if f.MustBeFile && f.MustBeDir {
+				err = declarationError("error: path flag '%s' cannot be both a file and a directory", name)
+				return
+			}
+			f.InitEnv(lookup)
 			vflags = append(vflags, f)
 
 		default:
-			err = fmt.Errorf("error: invalid data type for flag or flag pointer (%T). Use a FlagBool, FlagInt, FlagString, FlagStringSlice or nil value instead", v)
+			err = declarationError("error: invalid data type for flag or flag pointer (%T). Use a FlagBool, FlagInt, FlagString, FlagStringSlice, FlagPath or nil value instead", v)
 			return
 		}
 	}
@@ -130,6 +185,13 @@ func FindFlagByKey(key string, flags []flag.Flag) (index int, fl flag.Flag, isAl
 			if IsFlagAlias(key, f.Aliases) {
 				return i, f, true
 			}
+		case flag.FlagPath:
+			if IsFlagLong(f.Name, key) {
+				return i, f, false
+			}
+			if IsFlagAlias(key, f.Aliases) {
+				return i, f, true
+			}
 		}
 	}
 	return -1, nil, false
@@ -145,9 +207,44 @@ func IsFlagAlias(key string, aliases []string) bool {
 	return slices.Contains(aliases, key)
 }
 
+// FlagNames returns the long name and the aliases of a given flag.
+func FlagNames(fl flag.Flag) (name string, aliases []string) {
+	switch f := fl.(type) {
+	case flag.FlagBool:
+		return f.Name, f.Aliases
+	case flag.FlagInt:
+		return f.Name, f.Aliases
+	case flag.FlagString:
+		return f.Name, f.Aliases
+	case flag.FlagStringSlice:
+		return f.Name, f.Aliases
+	case flag.FlagPath:
+		return f.Name, f.Aliases
+	}
+	return "", nil
+}
+
+// MatchPrefix returns the unique names starting with a given prefix in their original order.
+// An exact name match is returned alone.
+func MatchPrefix(prefix string, names []string) []string {
+	var matches []string
+	for _, name := range names {
+		if name == prefix {
+			return []string{name}
+		}
+		if strings.HasPrefix(name, prefix) && !slices.Contains(matches, name) {
+			matches = append(matches, name)
+		}
+	}
+	return matches
+}
+
+// FlagInfo defines a flag with its index in the flags list.
 type FlagInfo struct {
 	Flag  flag.Flag
 	Index int
+	// The key is the `--<name>-file` variant of a secret string flag.
+	File bool
 }
 
 // BuildFlagMap creates a map of flags for quick lookups.
@@ -177,12 +274,27 @@ func BuildFlagMap(flags []flag.Flag) map[string]FlagInfo {
 			for _, alias := range ft.Aliases {
 				flagMap[alias] = info
 			}
+		case flag.FlagPath:
+			flagMap[ft.Name] = info
+			for _, alias := range ft.Aliases {
+				flagMap[alias] = info
+			}
+		}
+	}
+
+	// Add the file variants of the secret string flags unless already declared
+	for i, f := range flags {
+		if ft, ok := f.(flag.FlagString); ok && ft.Secret {
+			if _, exists := flagMap[ft.Name+"-file"]; !exists {
+				flagMap[ft.Name+"-file"] = FlagInfo{Flag: f, Index: i, File: true}
+			}
 		}
 	}
 	return flagMap
 }
 
 // IsValidToken checks for printable ASCII characters (letters and hyphen-minus).
+// It returns a `ParseError` of kind `KindInvalidDeclaration` if the token is not valid.
 func IsValidToken(token string, tokenType string) error {
 	precededByHyphen := false
 	for i := 0; i < len(token); i++ {
@@ -206,7 +318,23 @@ func IsValidToken(token string, tokenType string) error {
 		if (b >= 0x41 && b <= 0x5A) || (b >= 0x61 && b <= 0x7A) {
 			continue
 		}
-		return fmt.Errorf("error: %s '%s' contains invalid characters", tokenType, token)
+		err := declarationError("error: %s '%s' contains invalid characters", tokenType, token)
+		err.Token = token
+		if tokenType == "command" {
+			err.Command = token
+		} else {
+			err.Flag = token
+		}
+		return err
 	}
 	return nil
 }
+
+// declarationError creates a new invalid declaration error.
+func declarationError(format string, a ...any) *ParseError {
+	return &ParseError{
+		Kind:    KindInvalidDeclaration,
+		Index:   -1,
+		Message: fmt.Sprintf(format, a...),
+	}
+}
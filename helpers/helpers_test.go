@@ -335,6 +335,7 @@ func Test_ValidateAndInitFlags(t *testing.T) {
 					EnvVar:       "ENV_VERBOSE",
 					FlagValue:    flag.Value("true"),
 					FlagAssigned: false,
+					FlagSource:   flag.Source{Kind: flag.SourceEnv, EnvVar: "ENV_VERBOSE"},
 				},
 			},
 		},
@@ -765,3 +766,60 @@ func TestIsValidToken(t *testing.T) {
 		})
 	}
 }
+
+func Test_FindCommand(t *testing.T) {
+	cmds := []app.Cmd{
+		{Name: "list", Aliases: []string{"ls"}},
+		{Name: "copy", Aliases: []string{"cp"}},
+	}
+	tests := []struct {
+		name      string
+		key       string
+		wantIndex int
+		wantName  string
+	}{
+		{name: "should find a command by name", key: "copy", wantIndex: 1, wantName: "copy"},
+		{name: "should find a command by alias", key: "ls", wantIndex: 0, wantName: "list"},
+		{name: "should not find an unknown command", key: "move", wantIndex: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, cmd := helpers.FindCommand(tt.key, cmds)
+			assert.Equal(t, tt.wantIndex, index)
+			if tt.wantIndex < 0 {
+				assert.Nil(t, cmd)
+				return
+			}
+			assert.Equal(t, tt.wantName, cmd.Name)
+			assert.Same(t, &cmds[tt.wantIndex], cmd)
+		})
+	}
+}
+
+func Test_ValidateCommands_Aliases(t *testing.T) {
+	_, err := helpers.ValidateCommands([]app.Cmd{{Name: "list", Aliases: []string{"l s"}}})
+	assert.EqualError(t, err, "error: command 'list' alias 'l s' contains invalid characters")
+}
+
+func Test_MatchPrefix(t *testing.T) {
+	names := []string{"verbose", "version", "help", "verbose"}
+	assert.Equal(t, []string{"verbose", "version"}, helpers.MatchPrefix("ver", names))
+	assert.Equal(t, []string{"verbose"}, helpers.MatchPrefix("verb", names))
+	assert.Equal(t, []string{"help"}, helpers.MatchPrefix("help", []string{"help", "helper"}))
+	assert.Empty(t, helpers.MatchPrefix("x", names))
+}
+
+func TestBuildFlagMap_SecretFile(t *testing.T) {
+	token := flag.FlagString{Name: "token", Secret: true}
+	flags := []flag.Flag{token, flag.FlagString{Name: "host"}}
+
+	flagMap := helpers.BuildFlagMap(flags)
+	assert.Equal(t, helpers.FlagInfo{Flag: token, Index: 0, File: true}, flagMap["token-file"])
+	assert.Equal(t, helpers.FlagInfo{Flag: token, Index: 0}, flagMap["token"])
+	_, ok := flagMap["host-file"]
+	assert.False(t, ok)
+
+	declared := flag.FlagString{Name: "token-file"}
+	flagMap = helpers.BuildFlagMap(append(flags, declared))
+	assert.Equal(t, helpers.FlagInfo{Flag: declared, Index: 2}, flagMap["token-file"])
+}
@@ -0,0 +1,119 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/joseluisq/cline/flag"
+)
+
+// ExpandPath expands a leading `~` (the home directory) and the `$VAR` or `${VAR}` references of a given path
+// via a given lookup function (the process environment if nil) and optionally resolves it into an absolute path.
+func ExpandPath(path string, absolute bool, lookup flag.LookupEnvFunc) (string, error) {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") || (runtime.GOOS == "windows" && strings.HasPrefix(path, `~\`)) {
+		home, err := homeDir(lookup)
+		if err != nil {
+			return "", err
+		}
+		path = home + ExpandEnv(path[1:], lookup)
+	} else {
+		path = ExpandEnv(path, lookup)
+	}
+	if absolute && path != "" {
+		return filepath.Abs(path)
+	}
+	return path, nil
+}
+
+// homeDir returns the home directory via a given lookup function falling back to `os.UserHomeDir`.
+func homeDir(lookup flag.LookupEnvFunc) (string, error) {
+	if home, ok := lookup("HOME"); ok && home != "" {
+		return home, nil
+	}
+	if runtime.GOOS == "windows" {
+		if home, ok := lookup("USERPROFILE"); ok && home != "" {
+			return home, nil
+		}
+	}
+	return os.UserHomeDir()
+}
+
+// CheckPath checks a given resolved path against the constraints of a given path flag.
+// An empty path is not checked.
+func CheckPath(fl flag.FlagPath, path string) error {
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return pathError(fl, path, err, "error: unable to access path '%s' of flag '--%s'", path, fl.Name)
+	}
+	exists := err == nil
+	if fl.MustExist && !exists {
+		return pathError(fl, path, err, "error: path '%s' of flag '--%s' does not exist", path, fl.Name)
+	}
+	if exists && fl.MustBeFile && info.IsDir() {
+		return pathError(fl, path, nil, "error: path '%s' of flag '--%s' is not a file", path, fl.Name)
+	}
+	if exists && fl.MustBeDir && !info.IsDir() {
+		return pathError(fl, path, nil, "error: path '%s' of flag '--%s' is not a directory", path, fl.Name)
+	}
+	parent := filepath.Dir(path)
+	if fl.ParentMustExist {
+		if pinfo, err := os.Stat(parent); err != nil || !pinfo.IsDir() {
+			return pathError(fl, path, err, "error: parent directory '%s' of path '%s' of flag '--%s' does not exist", parent, path, fl.Name)
+		}
+	}
+	if fl.MustBeWritable {
+		if exists {
+			err = checkWritable(path, info.IsDir())
+		} else {
+			err = checkWritable(parent, true)
+		}
+		if err != nil {
+			return pathError(fl, path, err, "error: path '%s' of flag '--%s' is not writable", path, fl.Name)
+		}
+	}
+	return nil
+}
+
+// checkWritable checks if a given file or directory is writable by opening it
+// or by creating a temporary file inside it.
+func checkWritable(path string, isDir bool) error {
+	if !isDir {
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	}
+	f, err := os.CreateTemp(path, ".cline-write-*")
+	if err != nil {
+		return err
+	}
+	name := f.Name()
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
+
+// pathError returns an invalid value error of a given path flag.
+func pathError(fl flag.FlagPath, path string, err error, format string, a ...any) *ParseError {
+	return &ParseError{
+		Kind:    KindInvalidValue,
+		Index:   -1,
+		Flag:    fl.Name,
+		Token:   path,
+		Message: fmt.Sprintf(format, a...),
+		Err:     err,
+	}
+}
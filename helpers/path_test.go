@@ -0,0 +1,111 @@
+package helpers_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+func TestExpandPath(t *testing.T) {
+	home := filepath.Join("home", "me")
+	lookup := flag.LookupEnvMap(map[string]string{"HOME": home, "USERPROFILE": home, "APP": "tool"})
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "", want: ""},
+		{input: "~", want: home},
+		{input: "~/.config/$APP", want: home + "/.config/tool"},
+		{input: "${APP}/data", want: "tool/data"},
+		{input: "a~/b", want: "a~/b"},
+		{input: "~user/x", want: "~user/x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			path, err := helpers.ExpandPath(tt.input, false, lookup)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, path)
+		})
+	}
+
+	t.Run("should resolve absolute paths", func(t *testing.T) {
+		path, err := helpers.ExpandPath("$APP", true, lookup)
+		assert.NoError(t, err)
+		wd, _ := os.Getwd()
+		assert.Equal(t, filepath.Join(wd, "tool"), path)
+
+		path, err = helpers.ExpandPath("", true, lookup)
+		assert.NoError(t, err)
+		assert.Equal(t, "", path)
+	})
+}
+
+func TestCheckPath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(file, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+	nested := filepath.Join(missing, "file.txt")
+
+	tests := []struct {
+		name    string
+		flag    flag.FlagPath
+		path    string
+		wantErr string
+	}{
+		{name: "empty path", flag: flag.FlagPath{Name: "p", MustExist: true}, path: ""},
+		{name: "existing file", flag: flag.FlagPath{Name: "p", MustExist: true, MustBeFile: true, MustBeWritable: true}, path: file},
+		{name: "existing dir", flag: flag.FlagPath{Name: "p", MustExist: true, MustBeDir: true, MustBeWritable: true}, path: dir},
+		{name: "missing path with parent", flag: flag.FlagPath{Name: "p", ParentMustExist: true, MustBeWritable: true}, path: missing},
+		{name: "missing path without constraints", flag: flag.FlagPath{Name: "p", MustBeFile: true}, path: missing},
+		{
+			name:    "missing path",
+			flag:    flag.FlagPath{Name: "p", MustExist: true},
+			path:    missing,
+			wantErr: "error: path '" + missing + "' of flag '--p' does not exist",
+		},
+		{
+			name:    "not a file",
+			flag:    flag.FlagPath{Name: "p", MustBeFile: true},
+			path:    dir,
+			wantErr: "error: path '" + dir + "' of flag '--p' is not a file",
+		},
+		{
+			name:    "not a directory",
+			flag:    flag.FlagPath{Name: "p", MustBeDir: true},
+			path:    file,
+			wantErr: "error: path '" + file + "' of flag '--p' is not a directory",
+		},
+		{
+			name:    "missing parent",
+			flag:    flag.FlagPath{Name: "p", ParentMustExist: true},
+			path:    nested,
+			wantErr: "error: parent directory '" + missing + "' of path '" + nested + "' of flag '--p' does not exist",
+		},
+		{
+			name:    "not writable parent",
+			flag:    flag.FlagPath{Name: "p", MustBeWritable: true},
+			path:    nested,
+			wantErr: "error: path '" + nested + "' of flag '--p' is not writable",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := helpers.CheckPath(tt.flag, tt.path)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+			assert.ErrorIs(t, err, helpers.ErrInvalidValue)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+)
+
+// Plugin defines an external command executable named `<app name>-<command>`.
+type Plugin struct {
+	// The plugin command name.
+	Name string
+	// The plugin executable path.
+	Path string
+}
+
+// pluginDirs returns the directories to look up plugin executables in order of precedence.
+// The PATH variable is looked up via a given function falling back to `App.LookupEnv`
+// and the process environment.
+func pluginDirs(ap *app.App, lookup flag.LookupEnvFunc) []string {
+	if lookup == nil {
+		lookup = ap.LookupEnv
+	}
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	dirs := slices.Clone(ap.PluginDirs)
+	path, _ := lookup("PATH")
+	return append(dirs, filepath.SplitList(path)...)
+}
+
+// LookupPlugin finds the plugin executable for a given command name
+// in the application plugin directories first and then in PATH.
+// The PATH variable is looked up via a given function (`App.LookupEnv` or the process environment if nil).
+func LookupPlugin(ap *app.App, name string, lookup flag.LookupEnvFunc) (plugin Plugin, ok bool) {
+	if ap == nil || ap.Name == "" || IsValidToken(name, "command") != nil {
+		return
+	}
+	for _, dir := range pluginDirs(ap, lookup) {
+		if dir == "" {
+			continue
+		}
+		path, err := exec.LookPath(filepath.Join(dir, ap.Name+"-"+name))
+		if err == nil {
+			return Plugin{Name: name, Path: path}, true
+		}
+	}
+	return
+}
+
+// FindPlugins lists the plugin executables available for the application
+// in the application plugin directories and PATH, excluding the ones shadowed by declared commands or their aliases.
+// The PATH variable is looked up like in `LookupPlugin`.
+func FindPlugins(ap *app.App, lookup flag.LookupEnvFunc) (plugins []Plugin) {
+	if ap == nil || ap.Name == "" {
+		return
+	}
+	prefix := ap.Name + "-"
+	seen := make(map[string]bool)
+	for _, c := range ap.Commands {
+		seen[c.Name] = true
+		for _, a := range c.Aliases {
+			seen[a] = true
+		}
+	}
+	for _, dir := range pluginDirs(ap, lookup) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasPrefix(e.Name(), prefix) {
+				continue
+			}
+			name := strings.TrimPrefix(e.Name(), prefix)
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if seen[name] || IsValidToken(name, "command") != nil {
+				continue
+			}
+			path, err := exec.LookPath(filepath.Join(dir, e.Name()))
+			if err != nil {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package helpers_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+func writeScript(t *testing.T, dir string, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+content+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func Test_LookupPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins are not supported on Windows")
+	}
+
+	dir := t.TempDir()
+	path := writeScript(t, dir, "tool-foo", "exit 0")
+	writeScript(t, dir, "other-bar", "exit 0")
+	if err := os.WriteFile(filepath.Join(dir, "tool-noexec"), []byte(""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	noPath := flag.LookupEnvMap(map[string]string{})
+
+	tests := []struct {
+		name   string
+		ap     *app.App
+		cmd    string
+		lookup flag.LookupEnvFunc
+		want   helpers.Plugin
+		wantOk bool
+	}{
+		{
+			name:   "should find a plugin in the plugin dirs",
+			ap:     &app.App{Name: "tool", PluginDirs: []string{dir}},
+			cmd:    "foo",
+			lookup: noPath,
+			want:   helpers.Plugin{Name: "foo", Path: path},
+			wantOk: true,
+		},
+		{
+			name:   "should find a plugin in PATH",
+			ap:     &app.App{Name: "tool"},
+			cmd:    "foo",
+			lookup: flag.LookupEnvMap(map[string]string{"PATH": dir}),
+			want:   helpers.Plugin{Name: "foo", Path: path},
+			wantOk: true,
+		},
+		{
+			name:   "should look up PATH via the app lookup function",
+			ap:     &app.App{Name: "tool", LookupEnv: flag.LookupEnvMap(map[string]string{"PATH": dir})},
+			cmd:    "foo",
+			want:   helpers.Plugin{Name: "foo", Path: path},
+			wantOk: true,
+		},
+		{
+			name:   "should not find a plugin of another app",
+			ap:     &app.App{Name: "tool", PluginDirs: []string{dir}},
+			cmd:    "bar",
+			lookup: noPath,
+		},
+		{
+			name:   "should not find a non-executable plugin",
+			ap:     &app.App{Name: "tool", PluginDirs: []string{dir}},
+			cmd:    "noexec",
+			lookup: noPath,
+		},
+		{
+			name:   "should not find a plugin with an invalid name",
+			ap:     &app.App{Name: "tool", PluginDirs: []string{dir}},
+			cmd:    "../foo",
+			lookup: noPath,
+		},
+		{
+			name: "should not find a plugin without app name",
+			ap:   &app.App{PluginDirs: []string{dir}},
+			cmd:  "foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := helpers.LookupPlugin(tt.ap, tt.cmd, tt.lookup)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_FindPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins are not supported on Windows")
+	}
+
+	dir1 := t.TempDir()
+	dir2 := t.TempDir()
+	foo := writeScript(t, dir1, "tool-foo", "exit 0")
+	writeScript(t, dir2, "tool-foo", "exit 0")
+	bar := writeScript(t, dir2, "tool-bar", "exit 0")
+	writeScript(t, dir2, "tool-info", "exit 0")
+	writeScript(t, dir2, "tool-ls", "exit 0")
+	writeScript(t, dir2, "other-baz", "exit 0")
+
+	ap := &app.App{
+		Name:       "tool",
+		PluginDirs: []string{dir1},
+		Commands:   []app.Cmd{{Name: "info", Aliases: []string{"ls"}}},
+	}
+	got := helpers.FindPlugins(ap, flag.LookupEnvMap(map[string]string{"PATH": dir2}))
+	assert.Equal(t, []helpers.Plugin{
+		{Name: "foo", Path: foo},
+		{Name: "bar", Path: bar},
+	}, got)
+}
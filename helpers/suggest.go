@@ -0,0 +1,66 @@
+package helpers
+
+import (
+	"sort"
+	"strings"
+)
+
+// Suggest returns the candidates which are similar to the given word sorted by similarity.
+// The similarity is based on a case-insensitive edit distance (including transpositions)
+// no greater than a third of the word length (at least one).
+func Suggest(word string, candidates []string) []string {
+	lword := strings.ToLower(word)
+	maxDist := max(1, len(word)/3)
+
+	type match struct {
+		candidate string
+		dist      int
+	}
+	var matches []match
+	for _, c := range candidates {
+		if c == "" || c == word {
+			continue
+		}
+		if d := EditDistance(lword, strings.ToLower(c)); d <= maxDist {
+			matches = append(matches, match{candidate: c, dist: d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].dist < matches[j].dist
+	})
+
+	var suggestions []string
+	for _, m := range matches {
+		suggestions = append(suggestions, m.candidate)
+	}
+	return suggestions
+}
+
+// EditDistance returns the optimal string alignment distance between two strings.
+// It counts insertions, deletions, substitutions and transpositions of adjacent characters.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	la, lb := len(ra), len(rb)
+
+	d := make([][]int, la+1)
+	for i := range d {
+		d[i] = make([]int, lb+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= lb; j++ {
+		d[0][j] = j
+	}
+	for i := 1; i <= la; i++ {
+		for j := 1; j <= lb; j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[la][lb]
+}
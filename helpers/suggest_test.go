@@ -0,0 +1,97 @@
+package helpers_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
+)
+
+func Test_EditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "abc", b: "", want: 3},
+		{a: "verbos", b: "verbose", want: 1},
+		{a: "inof", b: "info", want: 1},
+		{a: "staus", b: "status", want: 1},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "ñame", b: "name", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, helpers.EditDistance(tt.a, tt.b))
+		})
+	}
+}
+
+func Test_Suggest(t *testing.T) {
+	tests := []struct {
+		name       string
+		word       string
+		candidates []string
+		want       []string
+	}{
+		{
+			name:       "should suggest a similar candidate",
+			word:       "verbos",
+			candidates: []string{"file", "verbose", "version"},
+			want:       []string{"verbose"},
+		},
+		{
+			name:       "should sort suggestions by similarity",
+			word:       "versio",
+			candidates: []string{"verbose", "version", "versions"},
+			want:       []string{"version", "versions"},
+		},
+		{
+			name:       "should compare case-insensitively",
+			word:       "INFO",
+			candidates: []string{"info"},
+			want:       []string{"info"},
+		},
+		{
+			name:       "should not suggest distant candidates",
+			word:       "zzz",
+			candidates: []string{"info", "run"},
+			want:       nil,
+		},
+		{
+			name:       "should not suggest the same word",
+			word:       "info",
+			candidates: []string{"info"},
+			want:       nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, helpers.Suggest(tt.word, tt.candidates))
+		})
+	}
+}
+
+func Test_FlagNames(t *testing.T) {
+	tests := []struct {
+		name        string
+		flag        flag.Flag
+		wantName    string
+		wantAliases []string
+	}{
+		{name: "bool", flag: flag.FlagBool{Name: "a", Aliases: []string{"x"}}, wantName: "a", wantAliases: []string{"x"}},
+		{name: "int", flag: flag.FlagInt{Name: "b"}, wantName: "b"},
+		{name: "string", flag: flag.FlagString{Name: "c"}, wantName: "c"},
+		{name: "string slice", flag: flag.FlagStringSlice{Name: "d"}, wantName: "d"},
+		{name: "invalid", flag: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, aliases := helpers.FlagNames(tt.flag)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantAliases, aliases)
+		})
+	}
+}
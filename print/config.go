@@ -0,0 +1,93 @@
+package print
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+)
+
+// Output formats of the effective flag values.
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+)
+
+// configEntry defines the effective value of a flag and its source.
+type configEntry struct {
+	Flag    string `json:"flag"`
+	Command string `json:"command,omitempty"`
+	Value   string `json:"value"`
+	Source  string `json:"source"`
+	EnvVar  string `json:"env,omitempty"`
+	File    string `json:"file,omitempty"`
+	Index   int    `json:"index,omitempty"`
+
+	src flag.Source
+}
+
+// PrintConfig prints the effective values of the application and command flags with their sources
+// using a given format (`table` or `json`).
+func PrintConfig(ap *app.App, cmd *app.Cmd, format string) error {
+	if ap == nil {
+		return fmt.Errorf("error: application instance not found")
+	}
+
+	entries := configEntries(ap.Flags, "")
+	if cmd != nil {
+		entries = append(entries, configEntries(cmd.Flags, cmd.Name)...)
+	}
+
+	w := ap.Stdout()
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case FormatTable, "":
+		tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(tw, "FLAG\tVALUE\tSOURCE")
+		for _, e := range entries {
+			name := "--" + e.Flag
+			if e.Command != "" {
+				name = e.Command + " " + name
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", name, e.Value, e.src)
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("error: unsupported output format '%s'", format)
+}
+
+// configEntries returns the effective values of a list of flags.
+func configEntries(flags []flag.Flag, cmdName string) []configEntry {
+	entries := make([]configEntry, 0, len(flags))
+	for _, fl := range flags {
+		var e configEntry
+		var src flag.Source
+		switch f := fl.(type) {
+		case flag.FlagBool:
+			e, src = configEntry{Flag: f.Name, Value: f.FlagValue.ToString()}, f.FlagSource
+		case flag.FlagInt:
+			e, src = configEntry{Flag: f.Name, Value: f.FlagValue.ToString()}, f.FlagSource
+		case flag.FlagString:
+			e, src = configEntry{Flag: f.Name, Value: f.MaskedValue()}, f.FlagSource
+		case flag.FlagStringSlice:
+			e, src = configEntry{Flag: f.Name, Value: f.FlagValue.ToString()}, f.FlagSource
+		case flag.FlagPath:
+			e, src = configEntry{Flag: f.Name, Value: f.FlagValue.ToString()}, f.FlagSource
+		default:
+			continue
+		}
+		e.Command = cmdName
+		e.src = src
+		e.Source = src.Kind.String()
+		e.EnvVar = src.EnvVar
+		e.File = src.File
+		e.Index = src.Index
+		entries = append(entries, e)
+	}
+	return entries
+}
@@ -0,0 +1,57 @@
+package print_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/joseluisq/cline/app"
+	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/print"
+)
+
+func TestPrintConfig(t *testing.T) {
+	newApp := func(out *bytes.Buffer) (*app.App, *app.Cmd) {
+		ap := &app.App{
+			Writer: out,
+			Flags: []flag.Flag{
+				flag.FlagString{Name: "host", FlagValue: "example.com", FlagSource: flag.Source{Kind: flag.SourceEnv, EnvVar: "TOOL_HOST"}},
+				flag.FlagInt{Name: "port", FlagValue: "80"},
+			},
+		}
+		cmd := &app.Cmd{
+			Name:  "info",
+			Flags: []flag.Flag{flag.FlagBool{Name: "trace", FlagValue: "true", FlagSource: flag.Source{Kind: flag.SourceArg, Index: 2}}},
+		}
+		return ap, cmd
+	}
+
+	t.Run("should print a table", func(t *testing.T) {
+		var out bytes.Buffer
+		ap, cmd := newApp(&out)
+		assert.NoError(t, print.PrintConfig(ap, cmd, print.FormatTable))
+		assert.Equal(t, ""+
+			"FLAG           VALUE         SOURCE\n"+
+			"--host         example.com   env TOOL_HOST\n"+
+			"--port         80            default\n"+
+			"info --trace   true          arg 2\n", out.String())
+	})
+
+	t.Run("should print json", func(t *testing.T) {
+		var out bytes.Buffer
+		ap, _ := newApp(&out)
+		assert.NoError(t, print.PrintConfig(ap, nil, print.FormatJSON))
+		assert.JSONEq(t, `[
+			{"flag": "host", "value": "example.com", "source": "env", "env": "TOOL_HOST"},
+			{"flag": "port", "value": "80", "source": "default"}
+		]`, out.String())
+	})
+
+	t.Run("should report errors", func(t *testing.T) {
+		var out bytes.Buffer
+		ap, _ := newApp(&out)
+		assert.EqualError(t, print.PrintConfig(ap, nil, "yaml"), "error: unsupported output format 'yaml'")
+		assert.EqualError(t, print.PrintConfig(nil, nil, print.FormatJSON), "error: application instance not found")
+	})
+}
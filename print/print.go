@@ -3,10 +3,12 @@ package print
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/joseluisq/cline/app"
 	"github.com/joseluisq/cline/flag"
+	"github.com/joseluisq/cline/helpers"
 )
 
 type flagStruct struct {
@@ -22,7 +24,22 @@ func PrintHelp(ap *app.App, cmd *app.Cmd) error {
 	if ap == nil {
 		return fmt.Errorf("error: application instance not found")
 	}
+	program := ap.Name
+	if cmd != nil {
+		program = ap.Name + " " + cmd.Name
+	}
+	return PrintHelpProgram(ap, cmd, program, nil)
+}
+
+// PrintHelpProgram prints current application flags and commands info (--help)
+// using the given program invocation (e.g. `tool info`) in the usage and hint lines.
+// The plugins are searched via the `PATH` of the given environment lookup function if not nil.
+func PrintHelpProgram(ap *app.App, cmd *app.Cmd, program string, lookup flag.LookupEnvFunc) error {
+	if ap == nil {
+		return fmt.Errorf("error: application instance not found")
+	}
 
+	w := ap.Stdout()
 	paddingLeft := strings.Repeat(" ", 3)
 	summary := ap.Summary
 	flags := ap.Flags
@@ -31,19 +48,19 @@ func PrintHelp(ap *app.App, cmd *app.Cmd) error {
 		flags = cmd.Flags
 	}
 
-	fmt.Printf("%s %s\n", ap.Name, ap.Version)
-	fmt.Printf("%s\n\n", summary)
+	fmt.Fprintf(w, "%s %s\n", ap.Name, ap.Version)
+	fmt.Fprintf(w, "%s\n\n", summary)
 
 	// TODO: subcommands support
-	fmt.Println("USAGE:")
+	fmt.Fprintln(w, "USAGE:")
 	if cmd == nil {
-		fmt.Printf("%s%s [OPTIONS] COMMAND\n\n", paddingLeft, ap.Name)
+		fmt.Fprintf(w, "%s%s [OPTIONS] COMMAND\n\n", paddingLeft, program)
 	} else {
-		fmt.Printf("%s%s %s [OPTIONS]\n\n", paddingLeft, ap.Name, cmd.Name)
+		fmt.Fprintf(w, "%s%s [OPTIONS]\n\n", paddingLeft, program)
 	}
 
 	// Print options
-	fmt.Printf("OPTIONS:\n")
+	fmt.Fprintf(w, "OPTIONS:\n")
 
 	var vflags []flagStruct
 	var fLen = 0
@@ -58,6 +75,11 @@ func PrintHelp(ap *app.App, cmd *app.Cmd) error {
 			Name: "version", Aliases: []string{"v"}, Summary: "Prints version information",
 		})
 	}
+	if ap.PrintConfig {
+		flags = append(flags, flag.FlagString{
+			Name: "print-config", Summary: "Prints the effective flag values and their sources (table or json)",
+		})
+	}
 
 	// Calculate app or command flags positions
 	for _, fl := range flags {
@@ -67,16 +89,19 @@ func PrintHelp(ap *app.App, cmd *app.Cmd) error {
 		switch f := fl.(type) {
 		case flag.FlagBool:
 			fname = f.Name
-			vFlag = flagStruct{name: f.Name, aliases: f.Aliases, summary: f.Summary, defaults: f.FlagValue.ToString(), envVar: f.EnvVar}
+			vFlag = flagStruct{name: f.Name, aliases: f.Aliases, summary: f.Summary, defaults: f.FlagValue.ToString(), envVar: envVarNames(f.EnvVar, f.EnvVars)}
 		case flag.FlagInt:
 			fname = f.Name
-			vFlag = flagStruct{name: f.Name, aliases: f.Aliases, summary: f.Summary, defaults: f.FlagValue.ToString(), envVar: f.EnvVar}
+			vFlag = flagStruct{name: f.Name, aliases: f.Aliases, summary: f.Summary, defaults: f.FlagValue.ToString(), envVar: envVarNames(f.EnvVar, f.EnvVars)}
 		case flag.FlagString:
 			fname = f.Name
-			vFlag = flagStruct{name: f.Name, aliases: f.Aliases, summary: f.Summary, defaults: f.FlagValue.ToString(), envVar: f.EnvVar}
+			vFlag = flagStruct{name: f.Name, aliases: f.Aliases, summary: f.Summary, defaults: f.MaskedValue(), envVar: envVarNames(f.EnvVar, f.EnvVars)}
 		case flag.FlagStringSlice:
 			fname = f.Name
-			vFlag = flagStruct{name: f.Name, aliases: f.Aliases, summary: f.Summary, defaults: f.FlagValue.ToString(), envVar: f.EnvVar}
+			vFlag = flagStruct{name: f.Name, aliases: f.Aliases, summary: f.Summary, defaults: f.FlagValue.ToString(), envVar: envVarNames(f.EnvVar, f.EnvVars)}
+		case flag.FlagPath:
+			fname = f.Name
+			vFlag = flagStruct{name: f.Name, aliases: f.Aliases, summary: f.Summary, defaults: f.FlagValue.ToString(), envVar: envVarNames(f.EnvVar, f.EnvVars)}
 		}
 		if len([]rune(fname)) > fLen {
 			fLen = len([]rune(fname))
@@ -130,25 +155,34 @@ func PrintHelp(ap *app.App, cmd *app.Cmd) error {
 		)
 
 		summary := strings.ReplaceAll(v.summary, "\n", "\n"+strings.Repeat(" ", len(line)))
-		fmt.Println(line + summary + defaultVal + envVar)
+		fmt.Fprintln(w, line+summary+defaultVal+envVar)
 	}
 
 	// Print app commands
 	if cmd == nil {
 		if len(ap.Commands) > 0 {
-			fmt.Printf("\n")
-			fmt.Printf("COMMANDS:\n")
+			fmt.Fprintf(w, "\n")
+			fmt.Fprintf(w, "COMMANDS:\n")
 
 			var vcmds [][]string
 			var cmdLen = 0
 			for _, c := range ap.Commands {
-				vcmds = append(vcmds, []string{c.Name, c.Summary})
-				if len([]rune(c.Name)) > cmdLen {
-					cmdLen = len([]rune(c.Name))
+				name := strings.Join(append([]string{c.Name}, c.Aliases...), ", ")
+				summary := c.Summary
+				if ap.DefaultCommand != "" && (ap.DefaultCommand == c.Name || slices.Contains(c.Aliases, ap.DefaultCommand)) {
+					if summary != "" {
+						summary += " "
+					}
+					summary += "[default]"
+				}
+				vcmds = append(vcmds, []string{name, summary})
+				if len([]rune(name)) > cmdLen {
+					cmdLen = len([]rune(name))
 				}
 			}
 			for _, c := range vcmds {
-				fmt.Printf(
+				fmt.Fprintf(
+					w,
 					"%s%s%s%s%s%s\n",
 					paddingLeft,
 					"",
@@ -161,13 +195,55 @@ func PrintHelp(ap *app.App, cmd *app.Cmd) error {
 				)
 			}
 
-			fmt.Printf("\n")
-			fmt.Printf("Run '%s COMMAND --help' for more information on a command\n", ap.Name)
+		}
+
+		// Print app plugins
+		var plugins []helpers.Plugin
+		if ap.Plugins {
+			plugins = helpers.FindPlugins(ap, lookup)
+		}
+		if len(plugins) > 0 {
+			fmt.Fprintf(w, "\n")
+			fmt.Fprintf(w, "PLUGINS:\n")
+
+			var pluginLen = 0
+			for _, p := range plugins {
+				if len([]rune(p.Name)) > pluginLen {
+					pluginLen = len([]rune(p.Name))
+				}
+			}
+			for _, p := range plugins {
+				fmt.Fprintf(
+					w,
+					"%s%s%s%s%s\n",
+					paddingLeft,
+					p.Name,
+					paddingLeft,
+					strings.Repeat(" ", pluginLen-len([]rune(p.Name))),
+					p.Path,
+				)
+			}
+		}
+
+		if len(ap.Commands) > 0 || len(plugins) > 0 {
+			fmt.Fprintf(w, "\n")
+			fmt.Fprintf(w, "Run '%s COMMAND --help' for more information on a command\n", program)
 		}
 	} else {
-		fmt.Printf("\n")
-		fmt.Printf("Run '%s %s --help' for more information about this command\n", ap.Name, cmd.Name)
+		fmt.Fprintf(w, "\n")
+		fmt.Fprintf(w, "Run '%s --help' for more information about this command\n", program)
 	}
 
 	return nil
 }
+
+// envVarNames returns the comma-separated environment variable names of a flag.
+func envVarNames(envVar string, envVars []string) string {
+	names := make([]string, 0, len(envVars)+1)
+	for _, name := range append([]string{envVar}, envVars...) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
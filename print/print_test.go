@@ -1,8 +1,11 @@
 package print_test
 
 import (
+	"bytes"
 	"errors"
 	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -174,3 +177,72 @@ func TestApp_printVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintHelp_Writer(t *testing.T) {
+	t.Run("should print help to the app writer", func(t *testing.T) {
+		var out bytes.Buffer
+		ap := NewApp(nil, nil)
+		ap.Writer = &out
+
+		assert.NoError(t, print.PrintHelp(ap, nil))
+		str := out.String()
+		assert.Contains(t, str, "enve 0.0.0")
+		assert.Contains(t, str, "USAGE:")
+		assert.Contains(t, str, "COMMANDS:")
+		assert.Contains(t, str, "Run 'enve COMMAND --help' for more information on a command")
+	})
+
+	t.Run("should print version to the app writer", func(t *testing.T) {
+		var out bytes.Buffer
+		ap := NewApp(nil, nil)
+		ap.Writer = &out
+
+		ap.PrintVersion()
+		assert.Contains(t, out.String(), "Version:       0.0.0")
+	})
+}
+
+func TestPrintHelp_Plugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugins are not supported on Windows")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "enve-hello")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", "")
+
+	var out bytes.Buffer
+	ap := NewApp(nil, nil)
+	ap.Writer = &out
+	ap.Plugins = true
+	ap.PluginDirs = []string{dir}
+
+	assert.NoError(t, print.PrintHelp(ap, nil))
+	assert.Contains(t, out.String(), "PLUGINS:\n   hello   "+path+"\n")
+}
+
+func TestPrintHelp_DefaultCommand(t *testing.T) {
+	var out bytes.Buffer
+	ap := NewApp(nil, nil)
+	ap.Writer = &out
+	ap.DefaultCommand = "info"
+
+	assert.NoError(t, print.PrintHelp(ap, nil))
+	assert.Contains(t, out.String(), "COMMANDS:\n   info   Show command information [default]\n")
+}
+
+func TestPrintHelp_EnvVars(t *testing.T) {
+	var out bytes.Buffer
+	ap := app.New()
+	ap.Name = "tool"
+	ap.Writer = &out
+	ap.Flags = []flag.Flag{
+		flag.FlagInt{Name: "level", Summary: "Log level", EnvVar: "TOOL_LEVEL", EnvVars: []string{"LEVEL"}},
+	}
+
+	assert.NoError(t, print.PrintHelp(ap, nil))
+	assert.Contains(t, out.String(), "[env: TOOL_LEVEL, LEVEL]")
+}